@@ -4,10 +4,12 @@ import (
 	"accountapi/data"
 	"accountapi/lib"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,16 +17,37 @@ import (
 
 // Config contains configuration parameters for the client.
 type Config struct {
-	Server             string
+	Server string
+	// MaxConnections is a fixed limit of requests in flight.
+	//
+	// Deprecated: Use Concurrency, MaxConnections is only used when Concurrency is not set.
 	MaxConnections     int
 	MaxIdleConnections int
 	Timeout            int
+	Retry              RetryPolicy    // Retries of failed requests, disabled by default.
+	RateLimit          RateLimit      // Requests per second, not limited by default.
+	Concurrency        Concurrency    // Adaptive limit of requests in flight, not limited by default.
+	Signer             *Signer        // Signs every request with an HTTP message signature, requests are not signed if nil.
+	TokenSource        TokenSource    // Provides bearer tokens for every request, e.g. ClientCredentials, no token is sent if nil.
+	Middleware         []Middleware   // Wraps every operation, the first middleware is the outermost.
+	CircuitBreaker     CircuitBreaker // Fails fast while the service is failing, disabled by default.
+	Logger             Logger         // Logs every operation with account identifiers redacted, nothing is logged if nil.
+	ModifyAttempts     int            // Read-modify-write attempts of Modify on version conflicts, DefaultModifyAttempts if not set.
 }
 
 // Client enables access to web service.
 type Client struct {
-	server     string
-	httpClient *http.Client
+	server      string
+	httpClient  *http.Client
+	retry       RetryPolicy
+	rateLimit   *rateLimiter
+	concurrency *concurrencyLimiter
+	signer      *Signer
+	tokens      TokenSource
+	breaker     *circuitBreaker
+	handler     Handler // Middleware chain around the circuit breaker and send.
+
+	modifyAttempts int
 }
 
 // New creates a new client, used to connect to web account service. Communication parameters can be set to optimize
@@ -32,7 +55,6 @@ type Client struct {
 func New(cfg Config) (*Client, error) {
 	transport := &http.Transport{}
 	if cfg.MaxIdleConnections > 0 {
-		transport.MaxConnsPerHost = cfg.MaxConnections
 		transport.MaxIdleConns = cfg.MaxIdleConnections
 		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnections
 	}
@@ -40,59 +62,119 @@ func New(cfg Config) (*Client, error) {
 		Transport: transport,
 		Timeout:   time.Duration(cfg.Timeout) * time.Second,
 	}
-	return &Client{
-		server:     cfg.Server,
-		httpClient: client,
-	}, nil
+	if cfg.Concurrency.Max <= 0 && cfg.MaxConnections > 0 {
+		cfg.Concurrency = Concurrency{Initial: cfg.MaxConnections, Min: cfg.MaxConnections, Max: cfg.MaxConnections}
+	}
+	c := &Client{
+		server:      cfg.Server,
+		httpClient:  client,
+		retry:       cfg.Retry.withDefaults(),
+		rateLimit:   newRateLimiter(cfg.RateLimit),
+		concurrency: newConcurrencyLimiter(cfg.Concurrency),
+		signer:      cfg.Signer,
+		tokens:      cfg.TokenSource,
+		breaker:     newCircuitBreaker(cfg.CircuitBreaker),
+
+		modifyAttempts: cfg.ModifyAttempts,
+	}
+	if credentials, ok := cfg.TokenSource.(*ClientCredentials); ok {
+		credentials.setTimeout(client.Timeout)
+	}
+	if c.modifyAttempts <= 0 {
+		c.modifyAttempts = DefaultModifyAttempts
+	}
+	middleware := cfg.Middleware
+	if cfg.Logger != nil {
+		middleware = append(middleware[:len(middleware):len(middleware)], loggingMiddleware(cfg.Logger))
+	}
+	c.handler = chain(middleware, c.breaker.wrap(c.send, c.probeHealth))
+	return c, nil
+}
+
+func (c *Client) get(ctx context.Context, op *Operation) error {
+	op.Method = "GET"
+	return c.handler(ctx, op)
 }
 
-func (c *Client) get(endpoint string, jsonResponse interface{}) error {
-	return c.doRequest("GET", endpoint, nil, jsonResponse)
+func (c *Client) post(ctx context.Context, op *Operation) error {
+	op.Method = "POST"
+	return c.handler(ctx, op)
 }
 
-func (c *Client) post(endpoint string, jsonRequest io.Reader, jsonResponse interface{}) error {
-	return c.doRequest("POST", endpoint, jsonRequest, jsonResponse)
+func (c *Client) patch(ctx context.Context, op *Operation) error {
+	op.Method = "PATCH"
+	return c.handler(ctx, op)
 }
 
-func (c *Client) delete(endpoint string, jsonRequest io.Reader, jsonResponse interface{}) error {
-	return c.doRequest("DELETE", endpoint, jsonRequest, jsonResponse)
+func (c *Client) delete(ctx context.Context, op *Operation) error {
+	op.Method = "DELETE"
+	return c.handler(ctx, op)
 }
 
-// doRequest sends HTTP request to the server and either inserts the response into jsonResponse
+// doRequest sends HTTP request to the server and either inserts the response into op.Response
 // or provides error message as ErrorAPI as a return value. Other http errors are returned
-// when there's a communication error.
-func (c *Client) doRequest(method string, endpoint string, jsonRequest io.Reader, jsonResponse interface{}) error {
-	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.server, endpoint), jsonRequest)
+// when there's a communication error or when ctx is cancelled or its deadline expires.
+func (c *Client) doRequest(ctx context.Context, op *Operation) error {
+	var body io.Reader
+	if op.Body != nil {
+		body = bytes.NewReader(op.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, op.Method, fmt.Sprintf("%s%s", c.server, op.Endpoint), body)
 	if err != nil {
 		return err
 	}
-	if method != "GET" {
+	for k, v := range op.Header {
+		req.Header[k] = v
+	}
+	if op.Method != "GET" {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	if c.tokens != nil {
+		op.token, err = c.tokens.Token(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+op.token)
+	}
+	if c.signer != nil {
+		if err := c.signer.Sign(req, op.Body); err != nil {
+			return err
+		}
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	op.StatusCode = resp.StatusCode
 	if isErrorStatus(resp.StatusCode) {
 		errMessage := data.ErrorMessage{}
+		errAPI := lib.NewErrorAPI(resp.StatusCode, "")
+		errAPI.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
 		err = json.NewDecoder(resp.Body).Decode(&errMessage)
 		if err != nil {
-			return lib.NewErrorAPI(resp.StatusCode, err.Error()) // Return status even if there's no valid error message returned.
+			errAPI.ErrorMessage = err.Error() // Return status even if there's no valid error message returned.
+		} else {
+			errAPI.ErrorMessage = errMessage.Message
 		}
-		return lib.NewErrorAPI(resp.StatusCode, errMessage.Message)
+		return errAPI
 	}
-	if method == "DELETE" { // DELETE does not return anything if it succeeds.
+	if op.Method == "DELETE" || op.Response == nil { // DELETE does not return anything if it succeeds.
 		return nil
 	}
-	err = json.NewDecoder(resp.Body).Decode(&jsonResponse)
+	err = json.NewDecoder(resp.Body).Decode(op.Response)
 	return err
 }
 
 // Health checks server connectivity.
 func (c *Client) Health() bool {
+	return c.HealthContext(context.Background())
+}
+
+// HealthContext checks server connectivity, the check is aborted when ctx is done.
+func (c *Client) HealthContext(ctx context.Context) bool {
 	jResult := data.HealthResponse{}
-	err := c.get("/v1/health", &jResult)
+	err := c.get(ctx, &Operation{Name: "Health", Endpoint: "/v1/health", Response: &jResult})
 	if err != nil {
 		return false
 	}
@@ -102,7 +184,21 @@ func (c *Client) Health() bool {
 // Create creates an account in the accont service. On success, it returns the
 // account data, returned by the server, otherwise it returns and ErrorAPI error that
 // includes the error message, returned by the server.
+// When a retried create is rejected as a duplicate (409) and the stored account is identical to
+// the requested one, an earlier attempt has succeeded and the stored account is returned.
 func (c *Client) Create(account *data.Account) (*data.Account, error) {
+	return c.CreateContext(context.Background(), account)
+}
+
+// CreateContext is Create with a context that can cancel the request or set its deadline.
+func (c *Client) CreateContext(ctx context.Context, account *data.Account) (*data.Account, error) {
+	created, _, err := c.create(ctx, account)
+	return created, err
+}
+
+// create sends the create request. When a retried create is rejected as a duplicate, it also returns
+// the stored account that was compared with account and found different, nil if it wasn't fetched.
+func (c *Client) create(ctx context.Context, account *data.Account) (*data.Account, *data.Account, error) {
 	requestType := data.Accounts // Force type "accounts" in every create request.
 	jResult := data.ResponseData{}
 	jRequest := data.RequestCreate{
@@ -111,26 +207,56 @@ func (c *Client) Create(account *data.Account) (*data.Account, error) {
 			OrganisationID: account.OrganisationID,
 			Type:           &requestType,
 			Attributes:     account.Attributes,
+			Relationships:  account.Relationships,
 		},
 	}
 	bin, err := json.Marshal(&jRequest)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	op := &Operation{
+		Name:      "Create",
+		AccountID: account.ID,
+		Endpoint:  "/v1/organisation/accounts",
+		Body:      bin,
+		Response:  &jResult,
+
+		resolvesDuplicate: true,
 	}
-	reader := bytes.NewReader(bin)
-	err = c.post("/v1/organisation/accounts", reader, &jResult)
+	err = c.post(ctx, op)
 	if err != nil {
-		return nil, err
+		if op.ambiguous && isStatus(err, http.StatusConflict) {
+			// An earlier attempt may have created the account.
+			stored, errFetch := c.FetchContext(ctx, account.ID)
+			if errFetch != nil {
+				return nil, nil, err
+			}
+			if sameAccount(stored, account) {
+				return stored, nil, nil
+			}
+			return nil, stored, err
+		}
+		return nil, nil, err
 	}
-	return accountFromResponse(&jResult), nil
+	return accountFromResponse(&jResult), nil, nil
 }
 
 // Fetch fetches an account by id. On success, it returns the account data,
 // returned by the server, otherwise it returns and ErrorAPI error that
 // includes the error message, returned by the server.
 func (c *Client) Fetch(id uuid.UUID) (*data.Account, error) {
+	return c.FetchContext(context.Background(), id)
+}
+
+// FetchContext is Fetch with a context that can cancel the request or set its deadline.
+func (c *Client) FetchContext(ctx context.Context, id uuid.UUID) (*data.Account, error) {
 	jResult := data.ResponseData{}
-	err := c.get(fmt.Sprintf("%s%s", "/v1/organisation/accounts/", id.String()), &jResult)
+	err := c.get(ctx, &Operation{
+		Name:      "Fetch",
+		AccountID: id,
+		Endpoint:  fmt.Sprintf("%s%s", "/v1/organisation/accounts/", id.String()),
+		Response:  &jResult,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -138,52 +264,93 @@ func (c *Client) Fetch(id uuid.UUID) (*data.Account, error) {
 }
 
 // List retrieves an array of accounts on pageNumber where the size of a page is defined by pageSize.
+// It is a shorthand for ListPage that doesn't return the page links, new code should use ListPage.
 // If pageNumber is omitted (defaults to 0), the function has to pass data.PNNone, and pageSize
 // can be omitted with data.PSNone. pageNumber can have two special values data.First or data.Last,
 // representing the first or the last page.
 // On success, List returns an array of accounts, returned by the server (can be empty array, but not nil),
 // otherwise it returns and ErrorAPI error that includes the error message, returned by the server.
 func (c *Client) List(pageNumber lib.PageNumber, pageSize lib.PageSize) (*[]data.Account, error) {
-	jResult := data.ResponseDataList{}
-	query := ""
+	return c.ListContext(context.Background(), pageNumber, pageSize)
+}
+
+// ListContext is List with a context that can cancel the request or set its deadline.
+func (c *Client) ListContext(ctx context.Context, pageNumber lib.PageNumber, pageSize lib.PageSize) (*[]data.Account, error) {
+	opts := ListOptions{}
 	switch pageNumber {
-	case lib.PNNone:
+	case lib.PNNone, lib.First:
 		break
-	case lib.First:
-		query = "page[number]=first"
 	case lib.Last:
-		query = "page[number]=last"
+		opts.LastPage = true
 	default:
-		if int(pageNumber) > 0 {
-			query = fmt.Sprintf("page[number]=%d", pageNumber)
-		} else {
+		if int(pageNumber) <= 0 {
 			return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("pageNumber=%d", pageNumber))
 		}
+		opts.PageNumber = int(pageNumber)
 	}
 	if pageSize != lib.PSNone {
-		query = query + "&"
-	}
-	switch pageSize {
-	case lib.PSNone:
-		break
-	default:
-		if int(pageSize) > 0 {
-			query = query + fmt.Sprintf("page[size]=%d", pageSize)
-		} else {
-			return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("pageSize=%d", pageNumber))
+		if int(pageSize) <= 0 {
+			return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("pageSize=%d", pageSize))
 		}
+		opts.PageSize = int(pageSize)
 	}
+	page, err := c.ListPageContext(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &page.Accounts, nil
+}
 
-	err := c.get(fmt.Sprintf("%s%s", "/v1/organisation/accounts/?", query), &jResult)
+// Update changes the attributes of the account, identified by account.ID, with optimistic concurrency:
+// account.Version has to match the stored version. On success, it returns the account data, returned
+// by the server, with the new version. If the stored version is different, it returns ErrorConflict,
+// otherwise an ErrorAPI error that includes the error message, returned by the server.
+func (c *Client) Update(account *data.Account) (*data.Account, error) {
+	return c.UpdateContext(context.Background(), account)
+}
+
+// UpdateContext is Update with a context that can cancel the request or set its deadline.
+func (c *Client) UpdateContext(ctx context.Context, account *data.Account) (*data.Account, error) {
+	requestType := data.Accounts
+	version := account.Version
+	jResult := data.ResponseData{}
+	jRequest := data.RequestUpdate{
+		Data: data.AccountData{
+			ID:             account.ID,
+			OrganisationID: account.OrganisationID,
+			Type:           &requestType,
+			Version:        &version,
+			Attributes:     account.Attributes,
+		},
+	}
+	bin, err := json.Marshal(&jRequest)
+	if err != nil {
+		return nil, err
+	}
+	err = c.patch(ctx, &Operation{
+		Name:      "Update",
+		AccountID: account.ID,
+		Endpoint:  fmt.Sprintf("%s%s", "/v1/organisation/accounts/", account.ID.String()),
+		Body:      bin,
+		Response:  &jResult,
+	})
 	if err != nil {
+		if errAPI, ok := lib.ErrorCauser(err).(*lib.ErrorAPI); ok && errAPI.StatusCode == http.StatusConflict {
+			return nil, lib.NewErrorConflict(version, errAPI.ErrorMessage)
+		}
 		return nil, err
 	}
-	return accountListFromResponse(&jResult), nil
+	return accountFromResponse(&jResult), nil
 }
 
 // Delete deletes the account identified by id and version.
 // No data but potential ErrorAPI error with the error message is returned.
 func (c *Client) Delete(id uuid.UUID, version int) error {
+	return c.DeleteContext(context.Background(), id, version)
+}
+
+// DeleteContext is Delete with a context that can cancel the request or set its deadline.
+func (c *Client) DeleteContext(ctx context.Context, id uuid.UUID, version int) error {
 	// As id is always a valid UUID, there's no need to perform additional validation before contacting the server.
 	jResult := data.ResponseData{}
 	jRequest := data.RequestDelete{
@@ -193,14 +360,80 @@ func (c *Client) Delete(id uuid.UUID, version int) error {
 		},
 	}
 	bin, err := json.Marshal(&jRequest)
-	reader := bytes.NewReader(bin)
-	err = c.delete(fmt.Sprintf("/v1/organisation/accounts/%s?version=%d", id.String(), version), reader, &jResult)
+	if err != nil {
+		return err
+	}
+	err = c.delete(ctx, &Operation{
+		Name:      "Delete",
+		AccountID: id,
+		Endpoint:  fmt.Sprintf("/v1/organisation/accounts/%s?version=%d", id.String(), version),
+		Body:      bin,
+		Response:  &jResult,
+	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// CreateOrGet creates an account like Create, but it is idempotent: if an account with the same ID
+// already exists and its organisation and attributes are identical to account, it returns the stored
+// account as if it was created. If the stored account is different, it returns ErrorMismatch.
+// A create that failed with a communication error or a timeout can be safely repeated with CreateOrGet.
+func (c *Client) CreateOrGet(account *data.Account) (*data.Account, error) {
+	return c.CreateOrGetContext(context.Background(), account)
+}
+
+// CreateOrGetContext is CreateOrGet with a context that can cancel the requests or set their deadline.
+func (c *Client) CreateOrGetContext(ctx context.Context, account *data.Account) (*data.Account, error) {
+	created, stored, err := c.create(ctx, account)
+	if !isStatus(err, http.StatusConflict) {
+		return created, err
+	}
+	if stored == nil {
+		var errFetch error
+		stored, errFetch = c.FetchContext(ctx, account.ID)
+		if isStatus(errFetch, http.StatusNotFound) {
+			return nil, err // The conflict isn't caused by the ID.
+		}
+		if errFetch != nil {
+			return nil, errFetch
+		}
+	}
+	if !sameAccount(stored, account) {
+		return nil, lib.NewErrorMismatch(stored.ID.String(), stored.Version)
+	}
+	return stored, nil
+}
+
+// sameAccount returns true if both accounts have the same IDs and attributes. Empty and nil lists
+// and an empty private identification are equal, as the server omits them.
+func sameAccount(a, b *data.Account) bool {
+	return a.ID == b.ID && a.OrganisationID == b.OrganisationID &&
+		reflect.DeepEqual(normalAttributes(a.Attributes), normalAttributes(b.Attributes))
+}
+
+// normalAttributes returns attributes with empty lists and an empty private identification set to nil.
+func normalAttributes(attributes data.Attributes) data.Attributes {
+	if len(attributes.Name) == 0 {
+		attributes.Name = nil
+	}
+	if len(attributes.AlternativeNames) == 0 {
+		attributes.AlternativeNames = nil
+	}
+	if attributes.PrivateIdentification != nil {
+		identification := *attributes.PrivateIdentification
+		if len(identification.Address) == 0 {
+			identification.Address = nil
+		}
+		attributes.PrivateIdentification = &identification
+		if reflect.DeepEqual(identification, data.PrivateIdentification{}) {
+			attributes.PrivateIdentification = nil
+		}
+	}
+	return attributes
+}
+
 // accountFromResponse copies the values from response into Account structure.
 func accountFromResponse(r *data.ResponseData) *data.Account {
 	if r == nil {
@@ -209,12 +442,12 @@ func accountFromResponse(r *data.ResponseData) *data.Account {
 	if *r.Data.Type != data.Accounts {
 		panic("response data can't be handled for type " + r.Data.Type.String())
 	}
-	return &data.Account{
-		ID:             r.Data.ID,
-		OrganisationID: r.Data.OrganisationID,
-		Version:        *r.Data.Version,
-		Attributes:     r.Data.Attributes,
+	account := accountFromData(&r.Data)
+	if r.Links != (data.ResponseLinks{}) {
+		links := r.Links
+		account.Links = &links
 	}
+	return &account
 }
 
 // accountListFromResponse copies the values from List response into an array of Accounts.
@@ -223,17 +456,38 @@ func accountListFromResponse(r *data.ResponseDataList) *[]data.Account {
 		return nil
 	}
 	accList := []data.Account{}
-	for _, d := range r.Data {
-		accList = append(accList, data.Account{
-			ID:             d.ID,
-			OrganisationID: d.OrganisationID,
-			Version:        *d.Version,
-			Attributes:     d.Attributes,
-		})
+	for i := range r.Data {
+		accList = append(accList, accountFromData(&r.Data[i]))
 	}
 	return &accList
 }
 
+// accountFromData copies the values of account data, returned by the server, into Account structure.
+func accountFromData(d *data.AccountData) data.Account {
+	account := data.Account{
+		ID:             d.ID,
+		OrganisationID: d.OrganisationID,
+		Attributes:     d.Attributes,
+		Relationships:  d.Relationships,
+	}
+	if d.Version != nil {
+		account.Version = *d.Version
+	}
+	if d.CreatedOn != nil {
+		account.CreatedOn = *d.CreatedOn
+	}
+	if d.ModifiedOn != nil {
+		account.ModifiedOn = *d.ModifiedOn
+	}
+	return account
+}
+
+// isStatus returns true if err is an ErrorAPI with the statusCode.
+func isStatus(err error, statusCode int) bool {
+	errAPI, ok := lib.ErrorCauser(err).(*lib.ErrorAPI)
+	return ok && errAPI.StatusCode == statusCode
+}
+
 // isErrorStatus returns true for response status codes that are not 2xx.
 func isErrorStatus(statusCode int) bool {
 	return statusCode < 200 || statusCode > 299
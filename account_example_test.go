@@ -14,7 +14,7 @@ import (
 func Example() {
 	client, err := account.New(account.Config{
 		Server:             "http://127.0.0.1:8080",
-		MaxConnections:     64,
+		Concurrency:        account.Concurrency{Max: 64},
 		MaxIdleConnections: 16,
 		Timeout:            3,
 	})
@@ -1,4 +1,5 @@
-// Client library tests assume an account API service is running, no mock server is implemented.
+// Client library tests assume an account API service is running, only tests of client-side behaviour
+// (cancellation, retries, ...) use a local httptest server.
 // Account service docker image: 'form3tech/interview-accountapi:v1.0.0-4-g63cf8434'
 package account_test
 
@@ -7,11 +8,16 @@ import (
 	"accountapi/data"
 	"accountapi/lib"
 	"accountapi/lib/test"
+	"context"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/biter777/countries"
 	"github.com/google/uuid"
@@ -20,8 +26,8 @@ import (
 
 const (
 	SERVER = "http://127.0.0.1:8080"
-	// TestMaxConnections has to be somewhat lower than the number of available PostgreSQL connections
-	// at the server (100 by default) or the server will issue "server_errors".
+	// TestMaxConnections is the upper bound of the adaptive concurrency limit. The limit backs off when the
+	// server runs out of PostgreSQL connections (100 by default) and issues "server_errors".
 	TestMaxConnections     = 80
 	TestMaxIdleConnections = 16
 	TestTimeout            = 3
@@ -37,7 +43,7 @@ func newClient() (*account.Client, error) {
 	}
 	return account.New(account.Config{
 		Server:             server,
-		MaxConnections:     TestMaxConnections,
+		Concurrency:        account.Concurrency{Max: TestMaxConnections},
 		MaxIdleConnections: TestMaxIdleConnections,
 		Timeout:            TestTimeout,
 	})
@@ -281,7 +287,7 @@ func TestParallelRequests(t *testing.T) {
 	}
 	client, err := account.New(account.Config{
 		Server:             server,
-		MaxConnections:     TestMaxConnections,
+		Concurrency:        account.Concurrency{Max: TestMaxConnections},
 		MaxIdleConnections: TestMaxIdleConnections,
 		Timeout:            TestLongTimeout,
 	})
@@ -360,6 +366,33 @@ func TestParallelRequests(t *testing.T) {
 	cleanDatabase(t, client)
 }
 
+// TestContextCancel verifies that a cancelled context aborts a request that the server doesn't answer.
+func TestContextCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	client, err := account.New(account.Config{
+		Server:  server.URL,
+		Timeout: TestLongTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.FetchContext(ctx, uuid.New())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+	if time.Since(start) >= TestLongTimeout*time.Second {
+		t.Error("Request was not aborted before the client timeout.")
+	}
+}
+
 // cleanDatabase deletes all accounts from the database.
 func cleanDatabase(t *testing.T, client *account.Client) {
 	for { // As there may be more accounts than the default pageSize, loop until all accounts are deleted.
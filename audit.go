@@ -0,0 +1,92 @@
+package account
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ListAuditEntries retrieves the audit entries of the record of recordType, identified by id, e.g. the changes
+// of an account. For accounts, the data before and after every change is decoded into Before and After.
+// On success, it returns an array of audit entries (can be empty, but not nil), otherwise it returns
+// ErrorInvalidArgument for an empty record type or an ErrorAPI error that includes the error message,
+// returned by the server.
+func (c *Client) ListAuditEntries(recordType data.RecordType, id uuid.UUID) ([]data.AuditEntry, error) {
+	return c.ListAuditEntriesContext(context.Background(), recordType, id)
+}
+
+// ListAuditEntriesContext is ListAuditEntries with a context that can cancel the request or set its deadline.
+func (c *Client) ListAuditEntriesContext(ctx context.Context, recordType data.RecordType, id uuid.UUID) ([]data.AuditEntry, error) {
+	if recordType == data.RTNone || !recordType.IsValid() {
+		return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("recordType=%d", recordType))
+	}
+	op := &Operation{
+		Name:     "ListAuditEntries",
+		Endpoint: fmt.Sprintf("/v1/audit/entries/%s/%s", recordType.String(), id.String()),
+	}
+	if recordType == data.Accounts {
+		op.AccountID = id
+	}
+	jResult := data.ResponseAuditEntryList{}
+	op.Response = &jResult
+	if err := c.get(ctx, op); err != nil {
+		return nil, err
+	}
+	entries := []data.AuditEntry{}
+	for _, d := range jResult.Data {
+		entry, err := auditEntryFromData(&d)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// auditEntryFromData copies the values from audit entry data into AuditEntry structure and decodes the
+// account data before and after the change.
+func auditEntryFromData(d *data.AuditEntryData) (*data.AuditEntry, error) {
+	entry := &data.AuditEntry{
+		ID:          d.ID,
+		ActionedBy:  d.Attributes.ActionedBy,
+		Description: d.Attributes.Description,
+		RecordType:  d.Attributes.RecordType,
+		RecordID:    d.Attributes.RecordID,
+		BeforeData:  d.Attributes.BeforeData,
+		AfterData:   d.Attributes.AfterData,
+	}
+	if d.Version != nil {
+		entry.Version = *d.Version
+	}
+	if d.Attributes.ActionTime != nil {
+		entry.ActionTime = *d.Attributes.ActionTime
+	}
+	if entry.RecordType != data.Accounts {
+		return entry, nil
+	}
+	var err error
+	if entry.Before, err = accountFromSnapshot(d.Attributes.BeforeData); err != nil {
+		return nil, err
+	}
+	if entry.After, err = accountFromSnapshot(d.Attributes.AfterData); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// accountFromSnapshot decodes the account data of an audit entry, nil if there is no data.
+func accountFromSnapshot(snapshot json.RawMessage) (*data.Account, error) {
+	if len(snapshot) == 0 || string(snapshot) == "null" {
+		return nil, nil
+	}
+	d := data.AccountData{}
+	if err := json.Unmarshal(snapshot, &d); err != nil {
+		return nil, err
+	}
+	account := accountFromData(&d)
+	return &account, nil
+}
@@ -0,0 +1,70 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestAuditEntries verifies that the account data before and after the changes is decoded.
+func TestAuditEntries(t *testing.T) {
+	acc := generateBasicAccount()
+	recordType := data.Accounts
+	snapshot := func(bankID string, version int) json.RawMessage {
+		attributes := acc.Attributes
+		attributes.BankID = bankID
+		b, _ := json.Marshal(&data.AccountData{
+			ID:             acc.ID,
+			OrganisationID: acc.OrganisationID,
+			Type:           &recordType,
+			Version:        &version,
+			Attributes:     attributes,
+		})
+		return b
+	}
+	actionTime := time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC)
+	entries := []data.AuditEntryData{
+		{ID: acc.ID, Attributes: data.AuditEntryAttributes{ActionedBy: "onboarding", RecordType: data.Accounts, RecordID: acc.ID,
+			AfterData: snapshot("400300", 0)}},
+		{ID: acc.ID, Attributes: data.AuditEntryAttributes{ActionedBy: "support", ActionTime: &actionTime, RecordType: data.Accounts, RecordID: acc.ID,
+			BeforeData: snapshot("400300", 0), AfterData: snapshot("400302", 1)}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audit/entries/accounts/"+acc.ID.String() {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&data.ResponseAuditEntryList{Data: entries})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	audit, err := client.ListAuditEntries(data.Accounts, acc.ID)
+	if err != nil {
+		t.Fatalf("ListAuditEntries should succeed: %v", err)
+	}
+	if len(audit) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(audit))
+	}
+	if created := audit[0]; created.Before != nil || created.After == nil || created.After.ID != acc.ID || created.ActionedBy != "onboarding" {
+		t.Errorf("Expected the created account without the data before, got %+v", created)
+	}
+	changed := audit[1]
+	if changed.Before == nil || changed.After == nil || changed.Before.Attributes.BankID != "400300" ||
+		changed.After.Attributes.BankID != "400302" || changed.After.Version != 1 {
+		t.Errorf("Expected the bank ID change from version 0 to 1, got %+v", changed)
+	}
+	if changed.ActionedBy != "support" || !changed.ActionTime.Equal(actionTime) {
+		t.Errorf("Expected the change by support at %v, got %s at %v", actionTime, changed.ActionedBy, changed.ActionTime)
+	}
+
+	if _, err := client.ListAuditEntries(data.RTNone, acc.ID); !lib.IsErrorInvalidArgument(err) {
+		t.Errorf("Expected ErrorInvalidArgument for an empty record type, got %v", err)
+	}
+}
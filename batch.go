@@ -0,0 +1,213 @@
+package account
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DefaultBatchWorkers is the number of concurrent operations of a batch when neither BatchOptions.Workers
+// nor the client's concurrency limit is set.
+const DefaultBatchWorkers = 8
+
+// BatchOptions configures batch operations.
+type BatchOptions struct {
+	Workers     int                   // Concurrent operations, the client's concurrency limit or DefaultBatchWorkers if not set.
+	StopOnError bool                  // Don't start new operations after an operation failed.
+	Progress    func(done, total int) // Called after every completed or aborted operation, never concurrently, done reaches total.
+}
+
+// BatchResult is the result of an operation of a batch: the account or the error of the operation.
+type BatchResult struct {
+	Account *data.Account
+	Err     error
+}
+
+// CreateBatch creates accounts with concurrent Create operations, bounded by opts.Workers and the
+// client's limits, and returns a result for every account in the order of accounts. With
+// opts.StopOnError, the operations that were not started after the first failure fail with
+// ErrorBatchAborted, the operations in flight are completed.
+func (c *Client) CreateBatch(accounts []*data.Account, opts BatchOptions) []BatchResult {
+	return c.CreateBatchContext(context.Background(), accounts, opts)
+}
+
+// CreateBatchContext is CreateBatch with a context that can cancel the requests or set their deadline.
+func (c *Client) CreateBatchContext(ctx context.Context, accounts []*data.Account, opts BatchOptions) []BatchResult {
+	results := make([]BatchResult, len(accounts))
+	c.runBatch(ctx, len(accounts), opts, func(ctx context.Context, i int) error {
+		if accounts[i] == nil {
+			results[i].Err = lib.NewErrorInvalidArgument("nil account")
+		} else {
+			results[i].Account, results[i].Err = c.CreateContext(ctx, accounts[i])
+		}
+		return results[i].Err
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
+
+// runBatch calls do for the indexes 0..n-1 with concurrent workers. With opts.StopOnError, abort is called
+// with ErrorBatchAborted for the indexes that were not started after do returned an error. Progress is
+// reported for both.
+func (c *Client) runBatch(ctx context.Context, n int, opts BatchOptions, do func(ctx context.Context, i int) error, abort func(i int, err error)) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = c.ConcurrencyLimit()
+	}
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+	var mu sync.Mutex // Guards next, done, failure and the progress callback.
+	next, done := 0, 0
+	var failure error
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				if next >= n {
+					mu.Unlock()
+					return
+				}
+				i := next
+				next++
+				var err error
+				if opts.StopOnError && failure != nil {
+					cause := failure
+					mu.Unlock()
+					abort(i, lib.NewErrorBatchAborted(cause))
+				} else {
+					mu.Unlock()
+					err = do(ctx, i)
+				}
+				mu.Lock()
+				done++
+				if err != nil && failure == nil {
+					failure = err
+				}
+				if opts.Progress != nil {
+					opts.Progress(done, n)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// IDVersion identifies a version of an account for DeleteMany.
+type IDVersion struct {
+	ID      uuid.UUID
+	Version int
+}
+
+// uniqueIDs returns ids without duplicates, in the order of their first occurrence.
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := map[uuid.UUID]bool{}
+	unique := []uuid.UUID{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// FetchMany fetches the accounts identified by ids with concurrent Fetch operations, duplicate ids are
+// fetched once. It returns the fetched accounts and the errors of the failed fetches, keyed by id.
+func (c *Client) FetchMany(ids []uuid.UUID, opts BatchOptions) (map[uuid.UUID]*data.Account, map[uuid.UUID]error) {
+	return c.FetchManyContext(context.Background(), ids, opts)
+}
+
+// FetchManyContext is FetchMany with a context that can cancel the requests or set their deadline.
+func (c *Client) FetchManyContext(ctx context.Context, ids []uuid.UUID, opts BatchOptions) (map[uuid.UUID]*data.Account, map[uuid.UUID]error) {
+	ids = uniqueIDs(ids)
+	results := make([]BatchResult, len(ids))
+	c.runBatch(ctx, len(ids), opts, func(ctx context.Context, i int) error {
+		results[i].Account, results[i].Err = c.FetchContext(ctx, ids[i])
+		return results[i].Err
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	accounts := map[uuid.UUID]*data.Account{}
+	errs := map[uuid.UUID]error{}
+	for i, result := range results {
+		if result.Err != nil {
+			errs[ids[i]] = result.Err
+		} else {
+			accounts[ids[i]] = result.Account
+		}
+	}
+	return accounts, errs
+}
+
+// DeleteMany deletes the versions of accounts with concurrent Delete operations, an id that is listed
+// more than once is deleted once with its first version. It returns the errors of the failed deletes,
+// keyed by id; the map is empty if all the accounts were deleted.
+func (c *Client) DeleteMany(accounts []IDVersion, opts BatchOptions) map[uuid.UUID]error {
+	return c.DeleteManyContext(context.Background(), accounts, opts)
+}
+
+// DeleteManyContext is DeleteMany with a context that can cancel the requests or set their deadline.
+func (c *Client) DeleteManyContext(ctx context.Context, accounts []IDVersion, opts BatchOptions) map[uuid.UUID]error {
+	seen := map[uuid.UUID]bool{}
+	unique := []IDVersion{}
+	for _, account := range accounts {
+		if !seen[account.ID] {
+			seen[account.ID] = true
+			unique = append(unique, account)
+		}
+	}
+	return c.deleteBatch(ctx, len(unique), opts, func(i int) uuid.UUID {
+		return unique[i].ID
+	}, func(ctx context.Context, i int) error {
+		return c.DeleteContext(ctx, unique[i].ID, unique[i].Version)
+	})
+}
+
+// DeleteManyIDs deletes the current versions of the accounts identified by ids, it fetches every account
+// to find its version before it is deleted. Duplicate ids are deleted once. It returns the errors of the
+// failed fetches and deletes, keyed by id; the map is empty if all the accounts were deleted.
+// An account that is updated between the fetch and the delete is not deleted.
+func (c *Client) DeleteManyIDs(ids []uuid.UUID, opts BatchOptions) map[uuid.UUID]error {
+	return c.DeleteManyIDsContext(context.Background(), ids, opts)
+}
+
+// DeleteManyIDsContext is DeleteManyIDs with a context that can cancel the requests or set their deadline.
+func (c *Client) DeleteManyIDsContext(ctx context.Context, ids []uuid.UUID, opts BatchOptions) map[uuid.UUID]error {
+	ids = uniqueIDs(ids)
+	return c.deleteBatch(ctx, len(ids), opts, func(i int) uuid.UUID {
+		return ids[i]
+	}, func(ctx context.Context, i int) error {
+		account, err := c.FetchContext(ctx, ids[i])
+		if err != nil {
+			return err
+		}
+		return c.DeleteContext(ctx, ids[i], account.Version)
+	})
+}
+
+// deleteBatch runs the n deletes of a batch and returns their errors keyed by the id of the i-th account.
+func (c *Client) deleteBatch(ctx context.Context, n int, opts BatchOptions, id func(i int) uuid.UUID, del func(ctx context.Context, i int) error) map[uuid.UUID]error {
+	results := make([]error, n)
+	c.runBatch(ctx, n, opts, func(ctx context.Context, i int) error {
+		results[i] = del(ctx, i)
+		return results[i]
+	}, func(i int, err error) {
+		results[i] = err
+	})
+	errs := map[uuid.UUID]error{}
+	for i, err := range results {
+		if err != nil {
+			errs[id(i)] = err
+		}
+	}
+	return errs
+}
@@ -0,0 +1,223 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// createServer creates accounts and rejects the accounts with the bank ID "invalid".
+func createServer(inFlight, maxInFlight *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for m := atomic.LoadInt32(maxInFlight); n > m && !atomic.CompareAndSwapInt32(maxInFlight, m, n); m = atomic.LoadInt32(maxInFlight) {
+		}
+		time.Sleep(time.Millisecond)
+		var req data.RequestCreate
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Data.Type == nil || *req.Data.Type != data.Accounts {
+			writeError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		if req.Data.Attributes.BankID == "invalid" {
+			writeError(w, http.StatusBadRequest, "invalid bank_id")
+			return
+		}
+		writeAccount(w, http.StatusCreated, &data.Account{
+			ID:             req.Data.ID,
+			OrganisationID: req.Data.OrganisationID,
+			Attributes:     req.Data.Attributes,
+		})
+	}))
+}
+
+// TestCreateBatch verifies per-item results, the worker bound and progress.
+func TestCreateBatch(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := createServer(&inFlight, &maxInFlight)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	accounts := []*data.Account{}
+	for i := 0; i < 20; i++ {
+		acc := generateAccounts(1)[0]
+		if i == 5 {
+			acc.Attributes.BankID = "invalid"
+		}
+		accounts = append(accounts, &acc)
+	}
+	accounts = append(accounts, nil)
+	progress := 0
+	results := client.CreateBatch(accounts, account.BatchOptions{
+		Workers: 4,
+		Progress: func(done, total int) {
+			if done != progress+1 || total != len(accounts) {
+				t.Errorf("Expected progress %d/%d, got %d/%d", progress+1, len(accounts), done, total)
+			}
+			progress = done
+		},
+	})
+	if len(results) != len(accounts) || progress != len(accounts) {
+		t.Fatalf("Expected %d results and progress calls, got %d and %d", len(accounts), len(results), progress)
+	}
+	for i, result := range results {
+		switch i {
+		case 5:
+			if !lib.IsErrorAPI(result.Err) {
+				t.Errorf("Expected ErrorAPI for the invalid account, got %v", result.Err)
+			}
+		case len(accounts) - 1:
+			if !lib.IsErrorInvalidArgument(result.Err) {
+				t.Errorf("Expected ErrorInvalidArgument for the nil account, got %v", result.Err)
+			}
+		default:
+			if result.Err != nil || result.Account.ID != accounts[i].ID {
+				t.Errorf("Expected account #%d created, got %+v", i, result)
+			}
+		}
+	}
+	if m := atomic.LoadInt32(&maxInFlight); m > 4 {
+		t.Errorf("Expected at most 4 creates in flight, got %d", m)
+	}
+}
+
+// TestCreateBatchStopOnError verifies that the accounts after the first failure are not created.
+func TestCreateBatchStopOnError(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := createServer(&inFlight, &maxInFlight)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	accounts := []*data.Account{}
+	for i := 0; i < 5; i++ {
+		acc := generateAccounts(1)[0]
+		if i == 2 {
+			acc.Attributes.BankID = "invalid"
+		}
+		accounts = append(accounts, &acc)
+	}
+	progress := 0
+	results := client.CreateBatch(accounts, account.BatchOptions{
+		Workers:     1,
+		StopOnError: true,
+		Progress:    func(done, total int) { progress = done },
+	})
+	if progress != len(accounts) {
+		t.Errorf("Expected progress to reach %d with the aborted accounts, got %d", len(accounts), progress)
+	}
+	if results[0].Err != nil || results[1].Err != nil || !lib.IsErrorAPI(results[2].Err) {
+		t.Errorf("Expected 2 created accounts and an ErrorAPI, got %+v", results[:3])
+	}
+	for _, result := range results[3:] {
+		if !lib.IsErrorBatchAborted(result.Err) || result.Account != nil {
+			t.Errorf("Expected ErrorBatchAborted, got %+v", result)
+		}
+	}
+}
+
+// storeServer serves Fetch and Delete of stored accounts and counts the requests by account.
+type storeServer struct {
+	mu       sync.Mutex
+	accounts map[uuid.UUID]*data.Account
+	requests map[uuid.UUID]int
+}
+
+func newStoreServer(accounts []data.Account) *storeServer {
+	s := &storeServer{accounts: map[uuid.UUID]*data.Account{}, requests: map[uuid.UUID]int{}}
+	for i := range accounts {
+		s.accounts[accounts[i].ID] = &accounts[i]
+	}
+	return s
+}
+
+func (s *storeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, err := uuid.Parse(strings.TrimPrefix(r.URL.Path, "/v1/organisation/accounts/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	s.requests[id]++
+	acc, ok := s.accounts[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case "GET":
+		writeAccount(w, http.StatusOK, acc)
+	case "DELETE":
+		if r.URL.Query().Get("version") != strconv.Itoa(acc.Version) {
+			writeError(w, http.StatusConflict, "invalid version")
+			return
+		}
+		delete(s.accounts, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// TestFetchMany verifies that duplicate ids are fetched once and results and errors are keyed by id.
+func TestFetchMany(t *testing.T) {
+	accounts := generateAccounts(3)
+	s := newStoreServer(accounts)
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	missing := uuid.New()
+	fetched, errs := client.FetchMany([]uuid.UUID{accounts[0].ID, accounts[1].ID, accounts[0].ID, missing, accounts[2].ID}, account.BatchOptions{})
+	if len(fetched) != 3 || len(errs) != 1 || !lib.IsErrorAPI(errs[missing]) {
+		t.Fatalf("Expected 3 accounts and the error of the missing account, got %v, %v", fetched, errs)
+	}
+	for _, acc := range accounts {
+		if fetched[acc.ID] == nil || fetched[acc.ID].ID != acc.ID {
+			t.Errorf("Expected account %s, got %+v", acc.ID, fetched[acc.ID])
+		}
+	}
+	if s.requests[accounts[0].ID] != 1 {
+		t.Errorf("Expected a single fetch of a duplicate id, got %d", s.requests[accounts[0].ID])
+	}
+}
+
+// TestDeleteMany verifies deletes with known versions and with versions, looked up by DeleteManyIDs.
+func TestDeleteMany(t *testing.T) {
+	accounts := generateAccounts(4)
+	accounts[1].Version = 2
+	accounts[3].Version = 5
+	s := newStoreServer(accounts)
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	errs := client.DeleteMany([]account.IDVersion{
+		{ID: accounts[0].ID, Version: 0},
+		{ID: accounts[1].ID, Version: 0}, // Stale version.
+		{ID: accounts[0].ID, Version: 0},
+	}, account.BatchOptions{Workers: 2})
+	if len(errs) != 1 || !lib.IsErrorAPI(errs[accounts[1].ID]) {
+		t.Errorf("Expected the error of the stale version, got %v", errs)
+	}
+	if s.requests[accounts[0].ID] != 1 {
+		t.Errorf("Expected a single delete of a duplicate id, got %d", s.requests[accounts[0].ID])
+	}
+
+	errs = client.DeleteManyIDs([]uuid.UUID{accounts[1].ID, accounts[2].ID, accounts[3].ID, accounts[3].ID}, account.BatchOptions{})
+	if len(errs) != 0 || len(s.accounts) != 0 {
+		t.Errorf("Expected all accounts deleted, got errors %v, %d accounts left", errs, len(s.accounts))
+	}
+	if s.requests[accounts[3].ID] != 2 {
+		t.Errorf("Expected a fetch and a delete of a duplicate id, got %d requests", s.requests[accounts[3].ID])
+	}
+}
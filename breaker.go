@@ -0,0 +1,203 @@
+package account
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBreakerWindow is used when CircuitBreaker.Window is not set.
+	DefaultBreakerWindow = 10 * time.Second
+	// DefaultBreakerCoolDown is used when CircuitBreaker.CoolDown is not set.
+	DefaultBreakerCoolDown = 5 * time.Second
+)
+
+// BreakerState is the state of the circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets requests through and counts their failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects requests with ErrorCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen probes the service with Health before closing the breaker.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	panic("String not implemented for this BreakerState value")
+}
+
+// CircuitBreaker configures the circuit breaker that stops sending requests to a failing service.
+// Failures are communication errors and 5xx responses of operations, after retries. When at least
+// MinRequests operations were completed in the current Window and the rate of failures reaches
+// FailureRate, the breaker opens and operations fail fast with ErrorCircuitOpen. After CoolDown the
+// breaker is half-open: the next operation probes the service with Health and the breaker closes
+// if the service is up or opens for another CoolDown if it isn't. A probe that is cancelled by the
+// operation's context leaves the breaker open without a new CoolDown, so the next operation probes again.
+// The zero value disables the circuit breaker.
+type CircuitBreaker struct {
+	FailureRate   float64                     // Failure rate (0-1) that opens the breaker, 0 disables the breaker.
+	MinRequests   int                         // Minimum number of operations in the window before the failure rate is evaluated.
+	Window        time.Duration               // Duration of the window, in which the operations are counted, defaults to DefaultBreakerWindow.
+	CoolDown      time.Duration               // Duration of the open state, defaults to DefaultBreakerCoolDown.
+	OnStateChange func(from, to BreakerState) // Called on every state change, e.g. to alert on it.
+}
+
+// circuitBreaker is the state of the circuit breaker, a nil circuitBreaker lets all operations through.
+type circuitBreaker struct {
+	cfg         CircuitBreaker
+	mu          sync.Mutex
+	state       BreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+}
+
+func newCircuitBreaker(cfg CircuitBreaker) *circuitBreaker {
+	if cfg.FailureRate <= 0 {
+		return nil
+	}
+	if cfg.MinRequests < 1 {
+		cfg.MinRequests = 1
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultBreakerWindow
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = DefaultBreakerCoolDown
+	}
+	return &circuitBreaker{
+		cfg:         cfg,
+		windowStart: time.Now(),
+	}
+}
+
+// wrap returns a handler that passes operations to next while the breaker is closed.
+// probe checks the service health in the half-open state.
+func (b *circuitBreaker) wrap(next Handler, probe func(ctx context.Context) bool) Handler {
+	if b == nil {
+		return next
+	}
+	return func(ctx context.Context, op *Operation) error {
+		if err := b.allow(ctx, probe); err != nil {
+			return err
+		}
+		err := next(ctx, op)
+		b.record(ctx, err)
+		return err
+	}
+}
+
+// allow returns ErrorCircuitOpen if the operation should not be sent, probing the service when the cool-down is over.
+func (b *circuitBreaker) allow(ctx context.Context, probe func(ctx context.Context) bool) error {
+	b.mu.Lock()
+	switch {
+	case b.state == BreakerClosed:
+		b.mu.Unlock()
+		return nil
+	case b.state == BreakerHalfOpen || time.Since(b.openedAt) < b.cfg.CoolDown:
+		b.mu.Unlock()
+		return lib.NewErrorCircuitOpen() // Still cooling down or another operation is probing.
+	}
+	b.setState(BreakerHalfOpen)
+
+	up := probe(ctx)
+	b.mu.Lock()
+	if !up && ctx.Err() != nil {
+		// A cancelled probe says nothing about the service, the next operation probes it again.
+		b.setState(BreakerOpen)
+		return ctx.Err()
+	}
+	if !up {
+		b.openedAt = time.Now()
+		b.setState(BreakerOpen)
+		return lib.NewErrorCircuitOpen()
+	}
+	b.windowStart, b.requests, b.failures = time.Now(), 0, 0
+	b.setState(BreakerClosed)
+	return nil
+}
+
+// record counts the outcome of an operation and opens the breaker when the failure rate is reached.
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return // Cancelled operations say nothing about the service.
+	}
+	b.mu.Lock()
+	if b.state != BreakerClosed {
+		b.mu.Unlock()
+		return
+	}
+	if time.Since(b.windowStart) > b.cfg.Window {
+		b.windowStart, b.requests, b.failures = time.Now(), 0, 0
+	}
+	b.requests++
+	if isServiceFailure(err) {
+		b.failures++
+	}
+	if b.requests >= b.cfg.MinRequests && float64(b.failures) >= b.cfg.FailureRate*float64(b.requests) {
+		b.openedAt = time.Now()
+		b.setState(BreakerOpen)
+		return
+	}
+	b.mu.Unlock()
+}
+
+// setState changes the state, releases the lock, held by the caller, and calls OnStateChange.
+func (b *circuitBreaker) setState(state BreakerState) {
+	from := b.state
+	b.state = state
+	b.mu.Unlock()
+	if from != state && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, state)
+	}
+}
+
+// current returns the current state.
+func (b *circuitBreaker) current() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// isServiceFailure returns true for errors that signal the service is down.
+func isServiceFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errAPI, ok := lib.ErrorCauser(err).(*lib.ErrorAPI); ok {
+		return errAPI.StatusCode >= 500
+	}
+	var errURL *url.Error
+	return errors.As(err, &errURL)
+}
+
+// probeHealth checks the service health, bypassing the middleware and the circuit breaker.
+func (c *Client) probeHealth(ctx context.Context) bool {
+	jResult := data.HealthResponse{}
+	err := c.send(ctx, &Operation{Name: "Health", Method: "GET", Endpoint: "/v1/health", Response: &jResult})
+	return err == nil && jResult.Status == "up"
+}
+
+// BreakerState returns the state of the circuit breaker, BreakerClosed if the client has no circuit breaker.
+func (c *Client) BreakerState() BreakerState {
+	return c.breaker.current()
+}
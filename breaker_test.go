@@ -0,0 +1,157 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreaker verifies that the breaker opens on failures, fails fast while open and closes after a successful health probe.
+func TestCircuitBreaker(t *testing.T) {
+	const COOLDOWN = 50 * time.Millisecond
+	acc := generateBasicAccount()
+	var down, requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			writeError(w, http.StatusServiceUnavailable, "down")
+			return
+		}
+		if r.URL.Path == "/v1/health" {
+			_ = json.NewEncoder(w).Encode(data.HealthResponse{Status: "up"})
+			return
+		}
+		writeAccount(w, http.StatusOK, acc)
+	}))
+	defer server.Close()
+
+	mu := sync.Mutex{}
+	transitions := []string{}
+	client, err := account.New(account.Config{
+		Server:  server.URL,
+		Timeout: TestTimeout,
+		CircuitBreaker: account.CircuitBreaker{
+			FailureRate: 0.5,
+			MinRequests: 4,
+			Window:      time.Minute,
+			CoolDown:    COOLDOWN,
+			OnStateChange: func(from, to account.BreakerState) {
+				mu.Lock()
+				transitions = append(transitions, from.String()+"->"+to.String())
+				mu.Unlock()
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	atomic.StoreInt32(&down, 1)
+	for i := 0; i < 4; i++ {
+		if _, err := client.Fetch(acc.ID); !lib.IsErrorAPI(err) {
+			t.Errorf("Expected ErrorAPI 503 before the breaker opens, got: %v", err)
+		}
+	}
+	if client.BreakerState() != account.BreakerOpen {
+		t.Fatalf("Expected an open breaker, got %s", client.BreakerState())
+	}
+	atomic.StoreInt32(&requests, 0)
+	if _, err := client.Fetch(acc.ID); !lib.IsErrorCircuitOpen(err) {
+		t.Errorf("Expected ErrorCircuitOpen, got: %v", err)
+	}
+	if client.Health() {
+		t.Error("Health should fail while the breaker is open")
+	}
+	if requests != 0 {
+		t.Errorf("No requests should be sent while the breaker is open, %d were sent", requests)
+	}
+
+	// The health probe fails, the breaker opens again.
+	time.Sleep(COOLDOWN)
+	if _, err := client.Fetch(acc.ID); !lib.IsErrorCircuitOpen(err) {
+		t.Errorf("Expected ErrorCircuitOpen after a failed probe, got: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected only the health probe to be sent, %d requests were sent", requests)
+	}
+
+	// The health probe succeeds, the breaker closes.
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(COOLDOWN)
+	if _, err := client.Fetch(acc.ID); err != nil {
+		t.Errorf("Fetch should succeed after a successful probe: %v", err)
+	}
+	if client.BreakerState() != account.BreakerClosed {
+		t.Errorf("Expected a closed breaker, got %s", client.BreakerState())
+	}
+
+	expected := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("Expected transitions %v, got %v", expected, transitions)
+			break
+		}
+	}
+}
+
+// TestCircuitBreakerCancelledProbe verifies that a probe, cancelled by the operation's context, doesn't restart the cool-down.
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	const COOLDOWN = 50 * time.Millisecond
+	acc := generateBasicAccount()
+	var down int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/health" {
+			time.Sleep(COOLDOWN)
+			_ = json.NewEncoder(w).Encode(data.HealthResponse{Status: "up"})
+			return
+		}
+		if atomic.LoadInt32(&down) == 1 {
+			writeError(w, http.StatusServiceUnavailable, "down")
+			return
+		}
+		writeAccount(w, http.StatusOK, acc)
+	}))
+	defer server.Close()
+	client, err := account.New(account.Config{
+		Server:         server.URL,
+		Timeout:        TestTimeout,
+		CircuitBreaker: account.CircuitBreaker{FailureRate: 0.5, MinRequests: 1, CoolDown: COOLDOWN},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Fetch(acc.ID); !lib.IsErrorAPI(err) || client.BreakerState() != account.BreakerOpen {
+		t.Fatalf("Expected an open breaker after 503, got %s: %v", client.BreakerState(), err)
+	}
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(COOLDOWN)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.FetchContext(ctx, acc.ID); lib.IsErrorCircuitOpen(err) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error of the cancelled probe, got: %v", err)
+	}
+	if client.BreakerState() != account.BreakerOpen {
+		t.Errorf("Expected an open breaker after a cancelled probe, got %s", client.BreakerState())
+	}
+	if _, err := client.Fetch(acc.ID); err != nil {
+		t.Errorf("The next Fetch should probe the service again and succeed: %v", err)
+	}
+	if client.BreakerState() != account.BreakerClosed {
+		t.Errorf("Expected a closed breaker, got %s", client.BreakerState())
+	}
+}
@@ -0,0 +1,119 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestCreateOrGet verifies that an identical existing account is returned and a different one is reported.
+func TestCreateOrGet(t *testing.T) {
+	var stored *data.Account
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if stored == nil {
+				writeError(w, http.StatusNotFound, "not found")
+				return
+			}
+			writeAccount(w, http.StatusOK, stored)
+		case "POST":
+			if stored != nil {
+				writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+				return
+			}
+			var req data.RequestCreate
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			stored = &data.Account{ID: req.Data.ID, OrganisationID: req.Data.OrganisationID, Attributes: req.Data.Attributes}
+			writeAccount(w, http.StatusCreated, stored)
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	acc := generateBasicAccount()
+	if _, err := client.CreateOrGet(acc); err != nil {
+		t.Fatalf("CreateOrGet should create the account: %v", err)
+	}
+	if _, err := client.Create(acc); !lib.IsErrorAPI(err) {
+		t.Errorf("Create of an existing account should fail with ErrorAPI, got %v", err)
+	}
+	got, err := client.CreateOrGet(acc)
+	if err != nil || got.ID != acc.ID {
+		t.Errorf("CreateOrGet of an identical account should return it, got %+v, %v", got, err)
+	}
+
+	different := *acc
+	different.Attributes.BankID = "400302"
+	_, err = client.CreateOrGet(&different)
+	if !lib.IsErrorMismatch(err) {
+		t.Fatalf("Expected ErrorMismatch, got %v", err)
+	}
+	if e := lib.ErrorCauser(err).(*lib.ErrorMismatch); e.ID != acc.ID.String() {
+		t.Errorf("Expected the mismatch of account %s, got %s", acc.ID, e.ID)
+	}
+}
+
+// TestCreateOrGetConflicts verifies that a conflict not caused by the ID is returned, that a retried create
+// fetches the stored account only once and that empty lists match the omitted ones.
+func TestCreateOrGetConflicts(t *testing.T) {
+	var stored *data.Account
+	var posts, gets int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			atomic.AddInt32(&gets, 1)
+			if stored == nil {
+				writeError(w, http.StatusNotFound, "not found")
+				return
+			}
+			writeAccount(w, http.StatusOK, stored)
+		case "POST":
+			if atomic.AddInt32(&posts, 1) == 1 && stored != nil {
+				writeError(w, http.StatusServiceUnavailable, "unavailable") // The stored account was created by an earlier request.
+				return
+			}
+			writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, testRetryPolicy)
+
+	acc := generateBasicAccount()
+	if _, err := client.CreateOrGet(acc); !isStatus(err, http.StatusConflict) {
+		t.Errorf("Expected the 409 of a conflict on another field, got %v", err)
+	}
+
+	stored = generateBasicAccount()
+	stored.ID = acc.ID
+	stored.Attributes.BankID = "400302"
+	atomic.StoreInt32(&posts, 0)
+	atomic.StoreInt32(&gets, 0)
+	if _, err := client.CreateOrGet(acc); !lib.IsErrorMismatch(err) {
+		t.Errorf("Expected ErrorMismatch after a retried create, got %v", err)
+	}
+	if n := atomic.LoadInt32(&gets); n != 1 {
+		t.Errorf("Expected the stored account to be fetched once, got %d", n)
+	}
+
+	// The server omits empty lists and an empty private identification.
+	stored = acc
+	withEmpty := *acc
+	withEmpty.Attributes.Name = []string{}
+	withEmpty.Attributes.AlternativeNames = []string{}
+	withEmpty.Attributes.PrivateIdentification = &data.PrivateIdentification{Address: []string{}}
+	if got, err := client.CreateOrGet(&withEmpty); err != nil || got.ID != acc.ID {
+		t.Errorf("Empty lists should match the omitted ones, got %+v, %v", got, err)
+	}
+}
+
+// isStatus returns true if err is an ErrorAPI with the statusCode.
+func isStatus(err error, statusCode int) bool {
+	errAPI, ok := lib.ErrorCauser(err).(*lib.ErrorAPI)
+	return ok && errAPI.StatusCode == statusCode
+}
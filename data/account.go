@@ -1,15 +1,22 @@
 package data
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 // Account represents a bank account, its structure follows https://api-docs.form3.tech/api.html#organisation-accounts-resource.
-// Fake account service does not implement "private_identification" and "relationships".
+// Fake account service does not implement "private_identification" and "relationships", they are omitted when nil.
+// CreatedOn, ModifiedOn and Links are set by the server, they are ignored when an account is created or updated.
 type Account struct {
-	Type           RecordType `json:"type"`
-	ID             uuid.UUID  `json:"id"`
-	OrganisationID uuid.UUID  `json:"organisation_id"`
-	Version        int        `json:"version"`
-	Attributes     Attributes `json:"attributes"`
+	Type           RecordType     `json:"type"`
+	ID             uuid.UUID      `json:"id"`
+	OrganisationID uuid.UUID      `json:"organisation_id"`
+	Version        int            `json:"version"`
+	CreatedOn      time.Time      `json:"created_on"`  // Zero if not returned by the server.
+	ModifiedOn     time.Time      `json:"modified_on"` // Zero if not returned by the server.
+	Attributes     Attributes     `json:"attributes"`
+	Relationships  *Relationships `json:"relationships,omitempty"`
+	Links          *ResponseLinks `json:"links,omitempty"` // Links of a created, fetched or updated account, nil in lists.
 }
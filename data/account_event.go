@@ -0,0 +1,24 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountEvent is an event in the lifecycle of an account, e.g. its creation, confirmation or failure.
+type AccountEvent struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganisationID uuid.UUID              `json:"organisation_id"`
+	Version        int                    `json:"version"`
+	CreatedOn      time.Time              `json:"created_on"`
+	Attributes     AccountEventAttributes `json:"attributes"`
+}
+
+// AccountEventAttributes of an account event.
+type AccountEventAttributes struct {
+	AccountID   uuid.UUID        `json:"account_id"`
+	EventType   AccountEventType `json:"event_type"`
+	Status      AccountStatus    `json:"status"`                // Status of the account after the event.
+	Description string           `json:"description,omitempty"` // Reason of a failure or other details.
+}
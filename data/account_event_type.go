@@ -0,0 +1,87 @@
+package data
+
+import (
+	"accountapi/lib"
+	"encoding/json"
+)
+
+// AccountEventType is the type of an event in the lifecycle of an account.
+type AccountEventType int
+
+const (
+	// EventCreated = "created".
+	EventCreated AccountEventType = iota
+	// EventConfirmed = "confirmed".
+	EventConfirmed
+	// EventFailed = "failed".
+	EventFailed
+	// EventUpdated = "updated".
+	EventUpdated
+	// EventDeleted = "deleted".
+	EventDeleted
+)
+
+// IsValid ...
+func (et AccountEventType) IsValid() bool {
+	switch et {
+	case EventCreated, EventConfirmed, EventFailed, EventUpdated, EventDeleted:
+		return true
+	}
+	return false
+}
+
+// String ...
+func (et AccountEventType) String() string {
+	switch et {
+	case EventCreated:
+		return "created"
+	case EventConfirmed:
+		return "confirmed"
+	case EventFailed:
+		return "failed"
+	case EventUpdated:
+		return "updated"
+	case EventDeleted:
+		return "deleted"
+	}
+	panic("String not implemented for this AccountEventType value")
+}
+
+// MarshalJSON converts values to strings.
+func (et *AccountEventType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(et.String())
+}
+
+// UnmarshalJSON converts string value names into const values.
+func (et *AccountEventType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	t, err := accountEventTypeParse(s)
+	if err != nil {
+		return err
+	}
+	*et = *t
+	return nil
+}
+
+// parse convers string value names into const values, returns ErrorInvalidEnum if string is unknown.
+func accountEventTypeParse(v string) (*AccountEventType, error) {
+	et := EventCreated
+	switch v {
+	case "created":
+		et = EventCreated
+	case "confirmed":
+		et = EventConfirmed
+	case "failed":
+		et = EventFailed
+	case "updated":
+		et = EventUpdated
+	case "deleted":
+		et = EventDeleted
+	default:
+		return nil, lib.NewErrorInvalidEnum()
+	}
+	return &et, nil
+}
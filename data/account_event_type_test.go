@@ -0,0 +1,63 @@
+package data_test
+
+import (
+	"accountapi/data"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestAccountEventType for testing unmarshalling JSON values.
+type TestAccountEventType struct {
+	TestET data.AccountEventType `json:"testET"`
+}
+
+// TestValidAccountEventType verifies proper constraints for consts ("enums"), parsing and unmarshalling.
+func TestValidAccountEventType(t *testing.T) {
+	et := data.EventConfirmed
+	if !et.IsValid() {
+		t.Error("AccountEventType EventConfirmed should be valid.")
+	}
+	if et.String() != "confirmed" {
+		t.Errorf("%s string should be \"confirmed\".", et.String())
+	}
+
+	jString := `{"testET":"confirmed"}`
+	jStruct := TestAccountEventType{}
+	err := json.NewDecoder(strings.NewReader(jString)).Decode(&jStruct)
+	if err != nil {
+		t.Errorf("Can't unmarshal AccountEventType: %s\n", err.Error())
+	}
+	if jStruct.TestET != et {
+		t.Errorf("Expected AccountEventType value: '%s', got: '%s'\n", et.String(), jStruct.TestET.String())
+	}
+	b, err := json.Marshal(&jStruct)
+	if err != nil {
+		t.Errorf("Can't marshal AccountEventType to string: %s\n", err.Error())
+	} else if string(b) != jString {
+		t.Errorf("Expected marshalled value: '%s', got: '%s'\n", jString, string(b))
+	}
+}
+
+// TestInvalidAccountEventType verifies response of functions when called with invalid proper constraints for consts ("enums"), parsing and unmarshalling.
+func TestInvalidAccountEventType(t *testing.T) {
+	et := data.EventDeleted // The last AccountEventType value, when it's increased it should become an invalid value.
+	et++
+	if et.IsValid() {
+		t.Error("Invalid AccountEventType not detected")
+	}
+
+	jString := `{"testET":"fake_event_type"}`
+	jStruct := TestAccountEventType{}
+	err := json.NewDecoder(strings.NewReader(jString)).Decode(&jStruct)
+	if err == nil {
+		t.Error("Unmarshalling should fail for invalid enum values")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Calling String on invalid AccountEventType value should panic.")
+		}
+	}()
+	_ = et.String() // This should panic.
+}
@@ -0,0 +1,23 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry records a change of a record, e.g. of an account, who made it and the data before and after it.
+type AuditEntry struct {
+	ID          uuid.UUID
+	Version     int
+	ActionedBy  string    // Identifies the user or the API client that made the change.
+	ActionTime  time.Time // Zero if not returned by the server.
+	Description string
+	RecordType  RecordType
+	RecordID    uuid.UUID
+	Before      *Account        // Account before the change, nil for a create or for other record types.
+	After       *Account        // Account after the change, nil for a delete or for other record types.
+	BeforeData  json.RawMessage // Record before the change, as returned by the server.
+	AfterData   json.RawMessage // Record after the change, as returned by the server.
+}
@@ -19,6 +19,11 @@ func NewCountryCode(c countries.CountryCode) CountryCode {
 	}
 }
 
+// IsValid ...
+func (c *CountryCode) IsValid() bool {
+	return c.countryCode.IsValid()
+}
+
 // String ...
 func (c *CountryCode) String() string {
 	return c.countryCode.Alpha2()
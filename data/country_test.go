@@ -21,6 +21,9 @@ func TestCountryCode(t *testing.T) {
 		t.Error("CountryCode string should be \"GB\".")
 		t.Fail()
 	}
+	if unknown := (data.CountryCode{}); !cc.IsValid() || unknown.IsValid() {
+		t.Error("Only a known CountryCode should be valid.")
+	}
 
 	jString := `{"testCC":"GB"}`
 	jStruct := TestCCode{}
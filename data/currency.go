@@ -19,6 +19,11 @@ func NewCurrency(c currency.Unit) Currency {
 	}
 }
 
+// IsValid returns false for the zero value, that stands for no currency (XXX).
+func (c *Currency) IsValid() bool {
+	return c.currency != currency.Unit{}
+}
+
 // String ...
 func (c *Currency) String() string {
 	return c.currency.String()
@@ -21,6 +21,12 @@ func TestCurrency(t *testing.T) {
 		t.Error("Currency string should be \"GBP\".")
 		t.Fail()
 	}
+	if !cc.IsValid() {
+		t.Error("GBP should be valid.")
+	}
+	if zero := (data.Currency{}); zero.IsValid() {
+		t.Error("Zero currency should not be valid.")
+	}
 
 	jString := `{"testCurrency":"GBP"}`
 	jStruct := TestCurrencyStruct{}
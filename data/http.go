@@ -1,6 +1,7 @@
 package data
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,13 +15,14 @@ type HealthResponse struct {
 // AccountData is an extended data struct for communicating with server.
 // The pointer types allow omitting optional data.
 type AccountData struct {
-	ID             uuid.UUID   `json:"id"`
-	OrganisationID uuid.UUID   `json:"organisation_id"`
-	Type           *RecordType `json:"type,omitempty"`
-	Version        *int        `json:"version,omitempty"`
-	CreatedOn      *time.Time  `json:"created_on,omitempty"`
-	ModifiedOn     *time.Time  `json:"modified_on,omitempty"`
-	Attributes     Attributes  `json:"attributes,omitempty"`
+	ID             uuid.UUID      `json:"id"`
+	OrganisationID uuid.UUID      `json:"organisation_id"`
+	Type           *RecordType    `json:"type,omitempty"`
+	Version        *int           `json:"version,omitempty"`
+	CreatedOn      *time.Time     `json:"created_on,omitempty"`
+	ModifiedOn     *time.Time     `json:"modified_on,omitempty"`
+	Attributes     Attributes     `json:"attributes,omitempty"`
+	Relationships  *Relationships `json:"relationships,omitempty"`
 }
 
 // ResponseData contains account data and links, a response from account service.
@@ -44,11 +46,115 @@ type ResponseLinks struct {
 	Prev  string `json:"prev,omitempty"`
 }
 
+// AccountEventData is the data of an account event, communicated with server.
+type AccountEventData struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganisationID uuid.UUID              `json:"organisation_id"`
+	Type           *RecordType            `json:"type,omitempty"`
+	Version        *int                   `json:"version,omitempty"`
+	CreatedOn      *time.Time             `json:"created_on,omitempty"`
+	Attributes     AccountEventAttributes `json:"attributes"`
+}
+
+// ResponseEvent contains account event data and links, a response from account service.
+type ResponseEvent struct {
+	Data  AccountEventData `json:"data,omitempty"`
+	Links ResponseLinks    `json:"links,omitempty"`
+}
+
+// ResponseEventList contains data with an array of AccountEventData, a response from account service ListEvents request.
+type ResponseEventList struct {
+	Data  []AccountEventData `json:"data,omitempty"`
+	Links ResponseLinks      `json:"links,omitempty"`
+}
+
+// OrganisationData is the data of an organisation unit, communicated with server.
+type OrganisationData struct {
+	ID         uuid.UUID              `json:"id"`
+	Type       *RecordType            `json:"type,omitempty"`
+	Version    *int                   `json:"version,omitempty"`
+	CreatedOn  *time.Time             `json:"created_on,omitempty"`
+	ModifiedOn *time.Time             `json:"modified_on,omitempty"`
+	Attributes OrganisationAttributes `json:"attributes"`
+}
+
+// ResponseOrganisation contains organisation data and links, a response from account service.
+type ResponseOrganisation struct {
+	Data  OrganisationData `json:"data,omitempty"`
+	Links ResponseLinks    `json:"links,omitempty"`
+}
+
+// ResponseOrganisationList contains data with an array of OrganisationData, a response from account service.
+type ResponseOrganisationList struct {
+	Data  []OrganisationData `json:"data,omitempty"`
+	Links ResponseLinks      `json:"links,omitempty"`
+}
+
+// RequestCreateOrganisation is passed to server to create an organisation unit.
+type RequestCreateOrganisation struct {
+	Data OrganisationData `json:"data"`
+}
+
+// AuditEntryData is the data of an audit entry, returned by server.
+type AuditEntryData struct {
+	ID         uuid.UUID            `json:"id"`
+	Version    *int                 `json:"version,omitempty"`
+	Attributes AuditEntryAttributes `json:"attributes"`
+}
+
+// AuditEntryAttributes of an audit entry, the record data before and after the change is decoded by record type.
+type AuditEntryAttributes struct {
+	ActionedBy  string          `json:"actioned_by"`
+	ActionTime  *time.Time      `json:"action_time,omitempty"`
+	Description string          `json:"description"`
+	RecordType  RecordType      `json:"record_type"`
+	RecordID    uuid.UUID       `json:"record_id"`
+	BeforeData  json.RawMessage `json:"before_data,omitempty"`
+	AfterData   json.RawMessage `json:"after_data,omitempty"`
+}
+
+// ResponseAuditEntryList contains data with an array of AuditEntryData, a response from audit entries request.
+type ResponseAuditEntryList struct {
+	Data  []AuditEntryData `json:"data,omitempty"`
+	Links ResponseLinks    `json:"links,omitempty"`
+}
+
+// SubscriptionData is the data of a subscription, communicated with server.
+type SubscriptionData struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganisationID uuid.UUID              `json:"organisation_id"`
+	Type           *RecordType            `json:"type,omitempty"`
+	Version        *int                   `json:"version,omitempty"`
+	Attributes     SubscriptionAttributes `json:"attributes"`
+}
+
+// ResponseSubscription contains subscription data and links, a response from notification service.
+type ResponseSubscription struct {
+	Data  SubscriptionData `json:"data,omitempty"`
+	Links ResponseLinks    `json:"links,omitempty"`
+}
+
+// ResponseSubscriptionList contains data with an array of SubscriptionData, a response from notification service.
+type ResponseSubscriptionList struct {
+	Data  []SubscriptionData `json:"data,omitempty"`
+	Links ResponseLinks      `json:"links,omitempty"`
+}
+
+// RequestCreateSubscription is passed to server to create a subscription.
+type RequestCreateSubscription struct {
+	Data SubscriptionData `json:"data"`
+}
+
 // RequestCreate is passed to server to create an account.
 type RequestCreate struct {
 	Data AccountData `json:"data"`
 }
 
+// RequestUpdate is passed to server to update an account, Data.Version has to match the stored version.
+type RequestUpdate struct {
+	Data AccountData `json:"data"`
+}
+
 // RequestDelete is passed to server to delete an account.
 type RequestDelete struct {
 	Data RequestDeleteData `json:"data"`
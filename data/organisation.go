@@ -0,0 +1,21 @@
+package data
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organisation is an organisation unit, the owner of accounts, identified by Account.OrganisationID.
+type Organisation struct {
+	ID         uuid.UUID              `json:"id"`
+	Version    int                    `json:"version"`
+	CreatedOn  time.Time              `json:"created_on"`  // Zero if not returned by the server.
+	ModifiedOn time.Time              `json:"modified_on"` // Zero if not returned by the server.
+	Attributes OrganisationAttributes `json:"attributes"`
+}
+
+// OrganisationAttributes of an organisation unit.
+type OrganisationAttributes struct {
+	Name string `json:"name"`
+}
@@ -6,7 +6,8 @@ import (
 )
 
 // RecordType is type of resource as defined in https://api-docs.form3.tech/api.html#audits-entries-record-types.
-// This library only supports a subset of types, related to account management: "accounts" and "account_events".
+// This library only supports a subset of types, related to account management: "accounts", "account_events",
+// "organisations" and "subscriptions".
 type RecordType int
 
 const (
@@ -16,12 +17,16 @@ const (
 	Accounts
 	// AccountEvents = "account_events". TODO: this type is missing from the list in the documentation.
 	AccountEvents
+	// Organisations = "organisations".
+	Organisations
+	// Subscriptions = "subscriptions".
+	Subscriptions
 )
 
 // IsValid ...
 func (rt *RecordType) IsValid() bool {
 	switch *rt {
-	case RTNone, Accounts, AccountEvents:
+	case RTNone, Accounts, AccountEvents, Organisations, Subscriptions:
 		return true
 	}
 	return false
@@ -36,6 +41,10 @@ func (rt *RecordType) String() string {
 		return "accounts"
 	case AccountEvents:
 		return "account_events"
+	case Organisations:
+		return "organisations"
+	case Subscriptions:
+		return "subscriptions"
 	}
 	panic("String not implemented for this RecordType value")
 }
@@ -67,6 +76,10 @@ func recordTypeParse(v string) (*RecordType, error) {
 		rt = Accounts
 	case "account_events":
 		rt = AccountEvents
+	case "organisations":
+		rt = Organisations
+	case "subscriptions":
+		rt = Subscriptions
 	default:
 		return nil, lib.NewErrorInvalidEnum()
 	}
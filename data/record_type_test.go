@@ -48,7 +48,7 @@ func TestValidRecordType(t *testing.T) {
 
 // TestInvalidRecordType verifies response of functions when called with invalid proper constraints for consts ("enums"), parsing and unmarshalling.
 func TestInvalidRecordType(t *testing.T) {
-	rt := data.AccountEvents // The last RecordType value, when it's increased it should become an invalid value.
+	rt := data.Subscriptions // The last RecordType value, when it's increased it should become an invalid value.
 	rt++
 	if rt.IsValid() {
 		t.Error("Invalid RecordType not detected")
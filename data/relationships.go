@@ -0,0 +1,33 @@
+package data
+
+import (
+	"github.com/google/uuid"
+)
+
+// PrivateIdentification of a personal account holder as defined in https://api-docs.form3.tech/api.html#organisation-accounts-resource.
+// Empty fields are omitted.
+type PrivateIdentification struct {
+	BirthDate      string       `json:"birth_date,omitempty"` // Date of birth, formatted as YYYY-MM-DD.
+	BirthCountry   *CountryCode `json:"birth_country,omitempty"`
+	Identification string       `json:"identification,omitempty"`
+	Address        []string     `json:"address,omitempty"`
+	City           string       `json:"city,omitempty"`
+	Country        *CountryCode `json:"country,omitempty"`
+}
+
+// Relationships of an account to other resources, nil relationships are omitted.
+type Relationships struct {
+	MasterAccount *Relationship `json:"master_account,omitempty"`
+	AccountEvents *Relationship `json:"account_events,omitempty"`
+}
+
+// Relationship lists the related resources.
+type Relationship struct {
+	Data []ResourceIdentifier `json:"data"`
+}
+
+// ResourceIdentifier identifies a related resource by its type and ID.
+type ResourceIdentifier struct {
+	Type RecordType `json:"type"`
+	ID   uuid.UUID  `json:"id"`
+}
@@ -0,0 +1,62 @@
+package data_test
+
+import (
+	"accountapi/data"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/biter777/countries"
+	"github.com/google/uuid"
+)
+
+// TestRelationships verifies marshalling of private identification and relationships and that they are omitted when empty.
+func TestRelationships(t *testing.T) {
+	acc := data.AccountData{ID: uuid.New()}
+	b, err := json.Marshal(&acc)
+	if err != nil {
+		t.Fatalf("Can't marshal AccountData: %s", err.Error())
+	}
+	if strings.Contains(string(b), "relationships") || strings.Contains(string(b), "private_identification") {
+		t.Errorf("Empty relationships and private identification should be omitted, got: '%s'", string(b))
+	}
+
+	gb := data.NewCountryCode(countries.UnitedKingdom)
+	acc.Attributes.Country = gb
+	master := uuid.MustParse("ea6239c1-99e9-4b5b-aa45-44e2f9c8b5f5")
+	acc.Attributes.PrivateIdentification = &data.PrivateIdentification{
+		BirthDate:      "2017-07-23",
+		BirthCountry:   &gb,
+		Identification: "13YH458762",
+		Address:        []string{"10 Avenue des Champs"},
+		City:           "London",
+	}
+	acc.Relationships = &data.Relationships{
+		MasterAccount: &data.Relationship{Data: []data.ResourceIdentifier{{Type: data.Accounts, ID: master}}},
+	}
+	b, err = json.Marshal(&acc)
+	if err != nil {
+		t.Fatalf("Can't marshal AccountData: %s", err.Error())
+	}
+	for _, expected := range []string{
+		`"private_identification":{"birth_date":"2017-07-23","birth_country":"GB","identification":"13YH458762","address":["10 Avenue des Champs"],"city":"London"}`,
+		`"relationships":{"master_account":{"data":[{"type":"accounts","id":"ea6239c1-99e9-4b5b-aa45-44e2f9c8b5f5"}]}}`,
+	} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("Expected '%s' in marshalled value: '%s'", expected, string(b))
+		}
+	}
+
+	decoded := data.AccountData{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Can't unmarshal AccountData: %s", err.Error())
+	}
+	pi := decoded.Attributes.PrivateIdentification
+	if pi == nil || pi.BirthCountry == nil || *pi.BirthCountry != gb || pi.Country != nil || pi.City != "London" {
+		t.Errorf("Unexpected private identification %+v", pi)
+	}
+	if decoded.Relationships == nil || decoded.Relationships.AccountEvents != nil ||
+		decoded.Relationships.MasterAccount.Data[0].ID != master || decoded.Relationships.MasterAccount.Data[0].Type != data.Accounts {
+		t.Errorf("Unexpected relationships %+v", decoded.Relationships)
+	}
+}
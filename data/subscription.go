@@ -0,0 +1,32 @@
+package data
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Subscription registers a callback URI that receives notifications when records of a type change.
+type Subscription struct {
+	ID             uuid.UUID              `json:"id"`
+	OrganisationID uuid.UUID              `json:"organisation_id"`
+	Version        int                    `json:"version"`
+	Attributes     SubscriptionAttributes `json:"attributes"`
+}
+
+// SubscriptionAttributes select the notifications of a subscription and where they are sent.
+type SubscriptionAttributes struct {
+	CallbackURI       string            `json:"callback_uri"`
+	CallbackTransport string            `json:"callback_transport"`   // "http", the default if empty.
+	RecordType        RecordType        `json:"record_type"`          // Accounts or AccountEvents.
+	EventType         *AccountEventType `json:"event_type,omitempty"` // All event types if nil.
+}
+
+// Notification is sent to the callback URI of a subscription, Data is the changed record of RecordType.
+type Notification struct {
+	ID             uuid.UUID        `json:"id"`
+	OrganisationID uuid.UUID        `json:"organisation_id"`
+	EventType      AccountEventType `json:"event_type"`
+	RecordType     RecordType       `json:"record_type"`
+	Data           json.RawMessage  `json:"data"`
+}
@@ -0,0 +1,95 @@
+package account
+
+import (
+	"accountapi/data"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// EventListOptions selects the page of the ListEvents request and the account of the events.
+type EventListOptions struct {
+	PageOptions
+	AccountID uuid.UUID // Only the events of the account are listed, all events if uuid.Nil.
+}
+
+// EventPage is a page of account events, returned by ListEvents, with links to the neighbouring pages.
+type EventPage struct {
+	Events []data.AccountEvent
+	Pagination
+}
+
+// ListEvents retrieves a page of account events, selected by opts, e.g. the lifecycle of an account.
+// On success, Events of the page is an array of events (can be empty, but not nil), otherwise it returns
+// ErrorInvalidArgument for invalid options or an ErrorAPI error that includes the error message,
+// returned by the server.
+func (c *Client) ListEvents(opts EventListOptions) (*EventPage, error) {
+	return c.ListEventsContext(context.Background(), opts)
+}
+
+// ListEventsContext is ListEvents with a context that can cancel the request or set its deadline.
+func (c *Client) ListEventsContext(ctx context.Context, opts EventListOptions) (*EventPage, error) {
+	query, err := opts.query()
+	if err != nil {
+		return nil, err
+	}
+	if opts.AccountID != uuid.Nil {
+		query.Set("filter[account_id]", opts.AccountID.String())
+	}
+	endpoint := "/v1/organisation/account_events"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	jResult := data.ResponseEventList{}
+	err = c.get(ctx, &Operation{
+		Name:      "ListEvents",
+		AccountID: opts.AccountID,
+		Endpoint:  endpoint,
+		Response:  &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := []data.AccountEvent{}
+	for i := range jResult.Data {
+		events = append(events, *eventFromData(&jResult.Data[i]))
+	}
+	return &EventPage{Events: events, Pagination: pageLinks(jResult.Links)}, nil
+}
+
+// FetchEvent fetches an account event by id. On success, it returns the event data, returned by the
+// server, otherwise it returns an ErrorAPI error that includes the error message, returned by the server.
+func (c *Client) FetchEvent(id uuid.UUID) (*data.AccountEvent, error) {
+	return c.FetchEventContext(context.Background(), id)
+}
+
+// FetchEventContext is FetchEvent with a context that can cancel the request or set its deadline.
+func (c *Client) FetchEventContext(ctx context.Context, id uuid.UUID) (*data.AccountEvent, error) {
+	jResult := data.ResponseEvent{}
+	err := c.get(ctx, &Operation{
+		Name:     "FetchEvent",
+		Endpoint: fmt.Sprintf("%s%s", "/v1/organisation/account_events/", id.String()),
+		Response: &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return eventFromData(&jResult.Data), nil
+}
+
+// eventFromData copies the values from event data into AccountEvent structure.
+func eventFromData(d *data.AccountEventData) *data.AccountEvent {
+	event := &data.AccountEvent{
+		ID:             d.ID,
+		OrganisationID: d.OrganisationID,
+		Attributes:     d.Attributes,
+	}
+	if d.Version != nil {
+		event.Version = *d.Version
+	}
+	if d.CreatedOn != nil {
+		event.CreatedOn = *d.CreatedOn
+	}
+	return event
+}
@@ -0,0 +1,76 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestEvents verifies listing the events of an account and fetching an event.
+func TestEvents(t *testing.T) {
+	accountID := uuid.New()
+	recordType := data.AccountEvents
+	version := 0
+	createdOn := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	events := []data.AccountEventData{}
+	for _, eventType := range []data.AccountEventType{data.EventCreated, data.EventConfirmed} {
+		events = append(events, data.AccountEventData{
+			ID:             uuid.New(),
+			OrganisationID: uuid.New(),
+			Type:           &recordType,
+			Version:        &version,
+			CreatedOn:      &createdOn,
+			Attributes:     data.AccountEventAttributes{AccountID: accountID, EventType: eventType, Status: data.Confirmed},
+		})
+	}
+	queries := make(chan url.Values, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/organisation/account_events":
+			queries <- r.URL.Query()
+			_ = json.NewEncoder(w).Encode(&data.ResponseEventList{
+				Data:  events,
+				Links: data.ResponseLinks{Next: "/v1/organisation/account_events?page%5Bnumber%5D=1&page%5Bsize%5D=2"},
+			})
+		case "/v1/organisation/account_events/" + events[1].ID.String():
+			_ = json.NewEncoder(w).Encode(&data.ResponseEvent{Data: events[1]})
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	page, err := client.ListEvents(account.EventListOptions{PageOptions: account.PageOptions{PageSize: 2}, AccountID: accountID})
+	if err != nil {
+		t.Fatalf("ListEvents should succeed: %v", err)
+	}
+	query := <-queries
+	if query.Get("filter[account_id]") != accountID.String() || query.Get("page[size]") != "2" {
+		t.Errorf("Expected the account filter and page size, got %v", query)
+	}
+	if len(page.Events) != 2 || !page.HasNext() || page.Links.Next.Number != 1 {
+		t.Fatalf("Expected 2 events and the next page 1, got %+v", page)
+	}
+	if page.Events[0].Attributes.EventType != data.EventCreated || page.Events[1].Attributes.EventType != data.EventConfirmed ||
+		!page.Events[1].CreatedOn.Equal(createdOn) || page.Events[1].Attributes.AccountID != accountID {
+		t.Errorf("Unexpected events %+v", page.Events)
+	}
+
+	event, err := client.FetchEvent(events[1].ID)
+	if err != nil || event.ID != events[1].ID || event.Attributes.EventType != data.EventConfirmed {
+		t.Errorf("Expected event %s, got %+v, %v", events[1].ID, event, err)
+	}
+	if _, err := client.FetchEvent(uuid.New()); !lib.IsErrorAPI(err) {
+		t.Errorf("Expected ErrorAPI for a missing event, got %v", err)
+	}
+}
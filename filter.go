@@ -0,0 +1,64 @@
+package account
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"net/url"
+	"strings"
+)
+
+// ListFilter selects accounts by their attributes, only accounts that match all the set fields are listed.
+// Empty fields are not filtered; a field with more values matches accounts with any of the values.
+type ListFilter struct {
+	BankID                string
+	BankIDCode            string
+	AccountNumber         string
+	IBAN                  string
+	CustomerID            string
+	Country               []data.CountryCode
+	BaseCurrency          []data.Currency
+	AccountClassification []data.AccountClass
+	Status                []data.AccountStatus
+}
+
+// addTo adds the filter[...] parameters of the set fields to query, multiple values are separated by commas.
+// It returns ErrorInvalidEnum if a value is not valid.
+func (f ListFilter) addTo(query url.Values) error {
+	values := map[string][]string{
+		"bank_id":        {f.BankID},
+		"bank_id_code":   {f.BankIDCode},
+		"account_number": {f.AccountNumber},
+		"iban":           {f.IBAN},
+		"customer_id":    {f.CustomerID},
+	}
+	for i := range f.Country {
+		if !f.Country[i].IsValid() {
+			return lib.NewErrorInvalidEnum()
+		}
+		values["country"] = append(values["country"], f.Country[i].String())
+	}
+	for i := range f.BaseCurrency {
+		if !f.BaseCurrency[i].IsValid() {
+			return lib.NewErrorInvalidEnum()
+		}
+		values["base_currency"] = append(values["base_currency"], f.BaseCurrency[i].String())
+	}
+	for i := range f.AccountClassification {
+		if !f.AccountClassification[i].IsValid() {
+			return lib.NewErrorInvalidEnum()
+		}
+		values["account_classification"] = append(values["account_classification"], f.AccountClassification[i].String())
+	}
+	for _, status := range f.Status {
+		if !status.IsValid() {
+			return lib.NewErrorInvalidEnum()
+		}
+		values["status"] = append(values["status"], status.String())
+	}
+	for key, v := range values {
+		if value := strings.Join(v, ","); value != "" {
+			query.Set("filter["+key+"]", value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/biter777/countries"
+	"golang.org/x/text/currency"
+)
+
+// TestListFilter verifies that typed filters are sent as comma separated, URL encoded filter parameters.
+func TestListFilter(t *testing.T) {
+	rawQueries := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQueries <- r.URL.RawQuery
+		writeError(w, http.StatusNotFound, "not found")
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	_, _ = client.ListPage(account.ListOptions{Filter: account.ListFilter{
+		IBAN:                  "GB11 NWBK+4003&0041",
+		Country:               []data.CountryCode{data.NewCountryCode(countries.UnitedKingdom), data.NewCountryCode(countries.France)},
+		BaseCurrency:          []data.Currency{data.NewCurrency(currency.GBP)},
+		AccountClassification: []data.AccountClass{data.Business},
+		Status:                []data.AccountStatus{data.Pending, data.Failed},
+	}})
+	expected := "filter%5Baccount_classification%5D=Business" +
+		"&filter%5Bbase_currency%5D=GBP" +
+		"&filter%5Bcountry%5D=GB%2CFR" +
+		"&filter%5Biban%5D=GB11+NWBK%2B4003%260041" +
+		"&filter%5Bstatus%5D=pending%2Cfailed"
+	if query := <-rawQueries; query != expected {
+		t.Errorf("Expected query %s, got %s", expected, query)
+	}
+
+	for _, filter := range []account.ListFilter{
+		{Country: []data.CountryCode{{}}},
+		{BaseCurrency: []data.Currency{{}}},
+		{AccountClassification: []data.AccountClass{data.AccountClass(10)}},
+		{Status: []data.AccountStatus{data.AccountStatus(10)}},
+	} {
+		if _, err := client.ListPage(account.ListOptions{Filter: filter}); !lib.IsErrorInvalidEnum(err) {
+			t.Errorf("Expected ErrorInvalidEnum for %+v, got %v", filter, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package account
+
+import (
+	"accountapi/data"
+	"context"
+	"net/url"
+)
+
+// Iterator iterates over all accounts of a list, page by page, following the next links, returned by
+// the server. The next page is fetched in the background while the accounts of the current page are
+// iterated, so at most two pages are held in memory.
+//
+//	it := client.ListAll(account.ListOptions{PageSize: 100})
+//	defer it.Close()
+//	for it.Next() {
+//		acc := it.Account()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pages   <-chan pageResult
+	page    []data.Account
+	index   int
+	account *data.Account
+	err     error
+	done    bool
+}
+
+// pageResult is a page or the error of its request, sent by the background fetch.
+type pageResult struct {
+	page *Page
+	err  error
+}
+
+// ListAll returns an iterator over all accounts, starting with the page selected by opts.
+// Filters and the page size of opts apply to all pages.
+func (c *Client) ListAll(opts ListOptions) *Iterator {
+	return c.ListAllContext(context.Background(), opts)
+}
+
+// ListAllContext is ListAll with a context; when the context is cancelled, the iteration stops
+// and Err returns the error of the context.
+func (c *Client) ListAllContext(ctx context.Context, opts ListOptions) *Iterator {
+	ctx, cancel := context.WithCancel(ctx)
+	pages := make(chan pageResult) // Unbuffered: only one page is fetched ahead.
+	it := &Iterator{ctx: ctx, cancel: cancel, pages: pages}
+	go c.fetchPages(ctx, opts, pages)
+	return it
+}
+
+// fetchPages sends the pages to the iterator until there is no next page, an error or ctx is done.
+func (c *Client) fetchPages(ctx context.Context, opts ListOptions, pages chan<- pageResult) {
+	defer close(pages)
+	page, err := c.ListPageContext(ctx, opts)
+	for {
+		select {
+		case pages <- pageResult{page: page, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil || len(page.Accounts) == 0 || !page.HasNext() {
+			return
+		}
+		var next string
+		if next, err = nextEndpoint(page); err == nil {
+			page, err = c.listEndpoint(ctx, next)
+		}
+	}
+}
+
+// nextEndpoint returns the path and query of the next link of page.
+func nextEndpoint(page *Page) (string, error) {
+	u, err := url.Parse(page.Links.Next.URL)
+	if err != nil {
+		return "", err
+	}
+	return u.RequestURI(), nil
+}
+
+// Next advances the iterator to the next account. It returns false at the end of the list,
+// on an error or when the context is cancelled; Err returns the error.
+func (it *Iterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		return it.stop(err)
+	}
+	for it.index >= len(it.page) {
+		it.page = nil
+		select {
+		case result, ok := <-it.pages:
+			if !ok || result.err != nil {
+				return it.stop(result.err)
+			}
+			it.page = result.page.Accounts
+			it.index = 0
+		case <-it.ctx.Done():
+			return it.stop(it.ctx.Err())
+		}
+	}
+	it.account = &it.page[it.index]
+	it.index++
+	return true
+}
+
+// stop ends the iteration with err and releases the context, it returns false.
+func (it *Iterator) stop(err error) bool {
+	it.err = err
+	it.done = true
+	it.account = nil
+	it.cancel()
+	return false
+}
+
+// Account returns the current account, nil before the first call of Next or after Next returned false.
+func (it *Iterator) Account() *data.Account {
+	return it.account
+}
+
+// Err returns the error that stopped the iteration, nil at the end of the list.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close stops the background fetch, it has to be called when the iteration is abandoned early.
+func (it *Iterator) Close() {
+	it.cancel()
+}
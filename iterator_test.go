@@ -0,0 +1,136 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// pagedServer serves a list of accounts in pages with links, like the account service.
+type pagedServer struct {
+	mu       sync.Mutex
+	accounts []data.Account
+	requests int32
+	onPage   func(s *pagedServer, number int) // Called with the lock held before a page is served.
+}
+
+func (s *pagedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.requests, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	size, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+	if size == 0 {
+		size = 1000
+	}
+	last := 0
+	if len(s.accounts) > 0 {
+		last = (len(s.accounts) - 1) / size
+	}
+	number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+	if r.URL.Query().Get("page[number]") == "last" {
+		number = last
+	}
+	if s.onPage != nil {
+		s.onPage(s, number)
+	}
+	list := data.ResponseDataList{Data: []data.AccountData{}}
+	recordType := data.Accounts
+	for i := number * size; i < (number+1)*size && i < len(s.accounts); i++ {
+		acc := s.accounts[i]
+		list.Data = append(list.Data, data.AccountData{
+			ID:             acc.ID,
+			OrganisationID: acc.OrganisationID,
+			Type:           &recordType,
+			Version:        &acc.Version,
+			Attributes:     acc.Attributes,
+		})
+	}
+	link := "/v1/organisation/accounts?page%%5Bnumber%%5D=%d&page%%5Bsize%%5D=%d"
+	list.Links.Self = fmt.Sprintf(link, number, size)
+	list.Links.First = fmt.Sprintf(link, 0, size)
+	list.Links.Last = fmt.Sprintf(link, last, size)
+	if number < last {
+		list.Links.Next = fmt.Sprintf(link, number+1, size)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&list)
+}
+
+func generateAccounts(n int) []data.Account {
+	accounts := make([]data.Account, n)
+	for i := range accounts {
+		accounts[i] = *generateBasicAccount()
+		accounts[i].ID = uuid.New()
+	}
+	return accounts
+}
+
+// TestListAll verifies that the iterator follows the next links through all pages.
+func TestListAll(t *testing.T) {
+	accounts := generateAccounts(25)
+	s := &pagedServer{accounts: accounts}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	it := client.ListAll(account.ListOptions{PageSize: 10})
+	defer it.Close()
+	i := 0
+	for it.Next() {
+		if i < len(accounts) && it.Account().ID != accounts[i].ID {
+			t.Errorf("Account #%d expected with id %s, got %s", i, accounts[i].ID, it.Account().ID)
+		}
+		i++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iteration should succeed: %v", err)
+	}
+	if i != len(accounts) {
+		t.Errorf("Expected %d accounts, got %d", len(accounts), i)
+	}
+	if n := atomic.LoadInt32(&s.requests); n != 3 {
+		t.Errorf("Expected 3 page requests, got %d", n)
+	}
+	if it.Next() || it.Account() != nil {
+		t.Error("Next after the end of the list should return false")
+	}
+}
+
+// TestListAllCancel verifies that the iteration stops with the error of the cancelled context.
+func TestListAllCancel(t *testing.T) {
+	s := &pagedServer{accounts: generateAccounts(50)}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := client.ListAllContext(ctx, account.ListOptions{PageSize: 5})
+	defer it.Close()
+	i := 0
+	for it.Next() {
+		if i++; i == 7 {
+			cancel()
+		}
+	}
+	if it.Err() != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", it.Err())
+	}
+	if i != 7 {
+		t.Errorf("Expected the iteration to stop after 7 accounts, got %d", i)
+	}
+	// At most the current and the prefetched page were requested.
+	if n := atomic.LoadInt32(&s.requests); n > 3 {
+		t.Errorf("Expected at most 3 page requests, got %d", n)
+	}
+}
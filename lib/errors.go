@@ -1,6 +1,9 @@
 package lib
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type causer interface {
 	Cause() error
@@ -16,9 +19,11 @@ func ErrorCauser(e error) error {
 }
 
 // ErrorAPI contains an error_message, returned by server.
+// RetryAfter holds the delay, requested by the server in the Retry-After header, or 0 if there was none.
 type ErrorAPI struct {
 	StatusCode   int
 	ErrorMessage string
+	RetryAfter   time.Duration
 }
 
 // NewErrorAPI ...
@@ -85,3 +90,156 @@ func IsErrorInvalidArgument(e error) bool {
 	_, ok := ErrorCauser(e).(*ErrorInvalidArgument)
 	return ok
 }
+
+// -------------------------------------------------------------------------
+
+// ErrorSignature denotes a missing or invalid HTTP message signature, Reason describes the problem.
+type ErrorSignature struct {
+	Reason string
+}
+
+// NewErrorSignature ...
+func NewErrorSignature(reason string) *ErrorSignature {
+	return &ErrorSignature{
+		Reason: reason,
+	}
+}
+
+// Error ...
+func (e *ErrorSignature) Error() string {
+	return "invalid signature: " + e.Reason
+}
+
+// IsErrorSignature ...
+func IsErrorSignature(e error) bool {
+	_, ok := ErrorCauser(e).(*ErrorSignature)
+	return ok
+}
+
+// -------------------------------------------------------------------------
+
+// ErrorToken denotes that no bearer token could be obtained from the token endpoint.
+// StatusCode is the status of the token endpoint's response, 0 if it couldn't be reached.
+type ErrorToken struct {
+	StatusCode   int
+	ErrorMessage string
+}
+
+// NewErrorToken ...
+func NewErrorToken(statusCode int, errMessage string) *ErrorToken {
+	return &ErrorToken{
+		StatusCode:   statusCode,
+		ErrorMessage: errMessage,
+	}
+}
+
+// Error ...
+func (e *ErrorToken) Error() string {
+	return fmt.Sprintf("token endpoint %d:%s", e.StatusCode, e.ErrorMessage)
+}
+
+// IsErrorToken ...
+func IsErrorToken(e error) bool {
+	_, ok := ErrorCauser(e).(*ErrorToken)
+	return ok
+}
+
+// -------------------------------------------------------------------------
+
+// ErrorCircuitOpen denotes that a request was not sent because the circuit breaker is open.
+type ErrorCircuitOpen struct{}
+
+// NewErrorCircuitOpen ...
+func NewErrorCircuitOpen() *ErrorCircuitOpen {
+	return &ErrorCircuitOpen{}
+}
+
+// Error ...
+func (ErrorCircuitOpen) Error() string {
+	return "circuit breaker is open"
+}
+
+// IsErrorCircuitOpen ...
+func IsErrorCircuitOpen(e error) bool {
+	_, ok := ErrorCauser(e).(*ErrorCircuitOpen)
+	return ok
+}
+
+// -------------------------------------------------------------------------
+
+// ErrorConflict denotes that an update was rejected because its version doesn't match the stored version.
+type ErrorConflict struct {
+	Version      int    // Version, sent with the rejected update.
+	ErrorMessage string // error_message, returned by server.
+}
+
+// NewErrorConflict ...
+func NewErrorConflict(version int, errMessage string) *ErrorConflict {
+	return &ErrorConflict{
+		Version:      version,
+		ErrorMessage: errMessage,
+	}
+}
+
+// Error ...
+func (e *ErrorConflict) Error() string {
+	return fmt.Sprintf("version %d conflict:%s", e.Version, e.ErrorMessage)
+}
+
+// IsErrorConflict ...
+func IsErrorConflict(e error) bool {
+	_, ok := ErrorCauser(e).(*ErrorConflict)
+	return ok
+}
+
+// -------------------------------------------------------------------------
+
+// ErrorBatchAborted denotes that an operation of a batch wasn't sent because an earlier operation failed.
+type ErrorBatchAborted struct {
+	Cause error // Error of the failed operation that aborted the batch.
+}
+
+// NewErrorBatchAborted ...
+func NewErrorBatchAborted(cause error) *ErrorBatchAborted {
+	return &ErrorBatchAborted{
+		Cause: cause,
+	}
+}
+
+// Error ...
+func (e *ErrorBatchAborted) Error() string {
+	return fmt.Sprintf("batch aborted:%v", e.Cause)
+}
+
+// IsErrorBatchAborted ...
+func IsErrorBatchAborted(e error) bool {
+	_, ok := ErrorCauser(e).(*ErrorBatchAborted)
+	return ok
+}
+
+// -------------------------------------------------------------------------
+
+// ErrorMismatch denotes that a record with the same ID already exists, but its data is different.
+type ErrorMismatch struct {
+	ID      string // ID of the existing record.
+	Version int    // Version of the existing record.
+}
+
+// NewErrorMismatch ...
+func NewErrorMismatch(id string, version int) *ErrorMismatch {
+	return &ErrorMismatch{
+		ID:      id,
+		Version: version,
+	}
+}
+
+// Error ...
+func (e *ErrorMismatch) Error() string {
+	return fmt.Sprintf("record %s version %d exists with different data", e.ID, e.Version)
+}
+
+// IsErrorMismatch ...
+func IsErrorMismatch(e error) bool {
+	_, ok := ErrorCauser(e).(*ErrorMismatch)
+	return ok
+}
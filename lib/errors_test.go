@@ -21,4 +21,30 @@ func TestErrors(t *testing.T) {
 		t.Errorf("Expected ErrorAPI(test_api_error), got '%s'", eAPI.Error())
 		t.Fail()
 	}
+
+	eToken := lib.NewErrorToken(401, "invalid_client")
+	if !lib.IsErrorToken(eToken) || lib.IsErrorAPI(eToken) {
+		t.Error("ErrorToken not recognised.")
+	}
+	if eToken.Error() != "token endpoint 401:invalid_client" {
+		t.Errorf("Expected ErrorToken(invalid_client), got '%s'", eToken.Error())
+	}
+
+	eConflict := lib.NewErrorConflict(3, "test_conflict")
+	if !lib.IsErrorConflict(eConflict) || lib.IsErrorAPI(eConflict) {
+		t.Error("ErrorConflict not recognised.")
+	}
+	if eConflict.Error() != "version 3 conflict:test_conflict" {
+		t.Errorf("Expected ErrorConflict(test_conflict), got '%s'", eConflict.Error())
+	}
+
+	eAborted := lib.NewErrorBatchAborted(eAPI)
+	if !lib.IsErrorBatchAborted(eAborted) || eAborted.Cause != eAPI {
+		t.Error("ErrorBatchAborted not recognised.")
+	}
+
+	eMismatch := lib.NewErrorMismatch("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", 1)
+	if !lib.IsErrorMismatch(eMismatch) || lib.IsErrorConflict(eMismatch) {
+		t.Error("ErrorMismatch not recognised.")
+	}
 }
@@ -0,0 +1,188 @@
+package account
+
+import (
+	"accountapi/lib"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultDecrease is the factor that reduces the concurrency limit on an overload when Concurrency.Decrease is not set.
+const DefaultDecrease = 0.5
+
+// RateLimit limits the rate of requests, sent to the server, with a token bucket.
+// The zero value disables the rate limit.
+type RateLimit struct {
+	RequestsPerSecond float64 // Rate at which the bucket is refilled.
+	Burst             int     // Capacity of the bucket, at least 1.
+}
+
+// Concurrency configures an adaptive (AIMD) limit of requests in flight. The limit starts at Initial,
+// grows by one for every Limit successful requests up to Max and is multiplied by Decrease when
+// the server reports an overload (429 or 5xx, e.g. "server_error" when its database pool is exhausted),
+// but never drops below Min. Setting Min, Initial and Max to the same value gives a fixed limit.
+// The zero value disables the limit.
+type Concurrency struct {
+	Initial  int     // Initial limit, defaults to Max.
+	Min      int     // Lower bound, defaults to 1.
+	Max      int     // Upper bound, 0 disables the limit.
+	Decrease float64 // Multiplicative decrease factor (0-1), defaults to DefaultDecrease.
+}
+
+// rateLimiter is a token bucket, a nil rateLimiter does not limit requests.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(cfg RateLimit) *rateLimiter {
+	if cfg.RequestsPerSecond <= 0 {
+		return nil
+	}
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+	return &rateLimiter{
+		rate:   cfg.RequestsPerSecond,
+		burst:  float64(cfg.Burst),
+		tokens: float64(cfg.Burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token from the bucket, waiting for it if the bucket is empty.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens-- // Reserve a token, the balance is negative when the caller has to wait for it.
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // Return the reserved token.
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// concurrencyLimiter is an AIMD limit of requests in flight, a nil concurrencyLimiter does not limit requests.
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	limit    float64
+	min      float64
+	max      float64
+	decrease float64
+	inFlight int
+	released chan struct{} // Closed and replaced when a request is released.
+}
+
+func newConcurrencyLimiter(cfg Concurrency) *concurrencyLimiter {
+	if cfg.Max <= 0 {
+		return nil
+	}
+	if cfg.Min < 1 {
+		cfg.Min = 1
+	}
+	if cfg.Min > cfg.Max {
+		cfg.Min = cfg.Max
+	}
+	if cfg.Initial < cfg.Min || cfg.Initial > cfg.Max {
+		cfg.Initial = cfg.Max
+	}
+	if cfg.Decrease <= 0 || cfg.Decrease >= 1 {
+		cfg.Decrease = DefaultDecrease
+	}
+	return &concurrencyLimiter{
+		limit:    float64(cfg.Initial),
+		min:      float64(cfg.Min),
+		max:      float64(cfg.Max),
+		decrease: cfg.Decrease,
+		released: make(chan struct{}),
+	}
+}
+
+// acquire waits until the number of requests in flight is below the limit and counts the request in.
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		if l.inFlight < int(l.limit) {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		released := l.released
+		l.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// release counts the request out and adapts the limit to the outcome of the request.
+func (l *concurrencyLimiter) release(err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.inFlight--
+	if isOverload(err) {
+		l.limit *= l.decrease
+		if l.limit < l.min {
+			l.limit = l.min
+		}
+	} else if err == nil {
+		l.limit += 1 / l.limit
+		if l.limit > l.max {
+			l.limit = l.max
+		}
+	}
+	close(l.released)
+	l.released = make(chan struct{})
+	l.mu.Unlock()
+}
+
+// current returns the current limit.
+func (l *concurrencyLimiter) current() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// isOverload returns true for errors that signal the server is overloaded.
+func isOverload(err error) bool {
+	errAPI, ok := lib.ErrorCauser(err).(*lib.ErrorAPI)
+	return ok && (errAPI.StatusCode == http.StatusTooManyRequests || errAPI.StatusCode >= 500)
+}
+
+// ConcurrencyLimit returns the current limit of requests in flight or 0 if the client doesn't limit them.
+func (c *Client) ConcurrencyLimit() int {
+	return c.concurrency.current()
+}
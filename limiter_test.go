@@ -0,0 +1,106 @@
+package account_test
+
+import (
+	account "accountapi"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestRateLimit verifies that requests above the burst wait for the token bucket to refill.
+func TestRateLimit(t *testing.T) {
+	const (
+		NREQUESTS = 6
+		RPS       = 50
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not found")
+	}))
+	defer server.Close()
+	client, err := account.New(account.Config{
+		Server:    server.URL,
+		Timeout:   TestTimeout,
+		RateLimit: account.RateLimit{RequestsPerSecond: RPS, Burst: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i := 0; i < NREQUESTS; i++ {
+		_, _ = client.Fetch(uuid.New())
+	}
+	if elapsed, min := time.Since(start), (NREQUESTS-1)*time.Second/RPS; elapsed < min {
+		t.Errorf("%d requests at %d requests per second took %v, expected at least %v", NREQUESTS, RPS, elapsed, min)
+	}
+}
+
+// TestConcurrencyLimit verifies that the number of requests in flight never exceeds the limit and
+// that the limit backs off on server errors and grows again on success.
+func TestConcurrencyLimit(t *testing.T) {
+	const (
+		NREQUESTS = 40
+		MAX       = 4
+	)
+	var inFlight, maxInFlight, failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if atomic.LoadInt32(&failing) == 1 {
+			writeError(w, http.StatusInternalServerError, "server_error")
+			return
+		}
+		writeAccount(w, http.StatusOK, generateBasicAccount())
+	}))
+	defer server.Close()
+	client, err := account.New(account.Config{
+		Server:      server.URL,
+		Timeout:     TestTimeout,
+		Concurrency: account.Concurrency{Max: MAX},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetchAll := func() {
+		wg := sync.WaitGroup{}
+		for i := 0; i < NREQUESTS; i++ {
+			wg.Add(1)
+			go func() {
+				_, _ = client.Fetch(uuid.New())
+				wg.Done()
+			}()
+		}
+		wg.Wait()
+	}
+
+	fetchAll()
+	if maxInFlight > MAX {
+		t.Errorf("Expected at most %d requests in flight, got %d", MAX, maxInFlight)
+	}
+	if client.ConcurrencyLimit() != MAX {
+		t.Errorf("Expected the limit to stay at %d, got %d", MAX, client.ConcurrencyLimit())
+	}
+
+	atomic.StoreInt32(&failing, 1)
+	fetchAll()
+	if client.ConcurrencyLimit() != 1 {
+		t.Errorf("Expected the limit to back off to 1 after server errors, got %d", client.ConcurrencyLimit())
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	fetchAll()
+	if client.ConcurrencyLimit() <= 1 {
+		t.Errorf("Expected the limit to grow after successful requests, got %d", client.ConcurrencyLimit())
+	}
+}
@@ -0,0 +1,176 @@
+package account
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions selects the page, filters and sort order of the ListPage request.
+// Zero values are omitted from the request, so the server defaults are used.
+type ListOptions struct {
+	PageNumber int        // Page number, starting with 0 for the first page.
+	LastPage   bool       // Fetch the last page, PageNumber is ignored.
+	PageSize   int        // Number of accounts on a page, the server default (1000) if 0.
+	Filter     ListFilter // Only accounts that match all the set fields are listed.
+	Sort       string     // Sort expression, e.g. "-created_on", sent as the sort parameter.
+}
+
+// PageOptions selects a page of the list requests of other resources than accounts.
+// Zero values are omitted from the request, so the server defaults are used.
+type PageOptions struct {
+	PageNumber int  // Page number, starting with 0 for the first page.
+	LastPage   bool // Fetch the last page, PageNumber is ignored.
+	PageSize   int  // Number of records on a page, the server default if 0.
+}
+
+// Page is a page of accounts, returned by ListPage, with links to the neighbouring pages.
+type Page struct {
+	Accounts []data.Account
+	Pagination
+}
+
+// Pagination holds the links of a page to the other pages, it is embedded in the pages of every resource.
+type Pagination struct {
+	Links PageLinks
+}
+
+// PageLinks are the links of a page, returned by the server, nil if the server didn't return the link.
+type PageLinks struct {
+	Self  *PageLink
+	First *PageLink
+	Last  *PageLink
+	Next  *PageLink
+	Prev  *PageLink
+}
+
+// PageLink is a link to a page with the page number and size, parsed from the link.
+type PageLink struct {
+	URL    string
+	Number int // Page number, -1 if the link has no numeric page number.
+	Size   int // Page size, 0 if the link has no page size.
+}
+
+// HasNext returns true if there is a page after this one.
+func (p *Pagination) HasNext() bool {
+	return p.Links.Next != nil
+}
+
+// HasPrev returns true if there is a page before this one.
+func (p *Pagination) HasPrev() bool {
+	return p.Links.Prev != nil
+}
+
+// query returns the URL query of the options, ErrorInvalidArgument if a page number or size is negative
+// or ErrorInvalidEnum if a filter value is invalid.
+func (o ListOptions) query() (url.Values, error) {
+	query, err := PageOptions{PageNumber: o.PageNumber, LastPage: o.LastPage, PageSize: o.PageSize}.query()
+	if err != nil {
+		return nil, err
+	}
+	if err := o.Filter.addTo(query); err != nil {
+		return nil, err
+	}
+	if o.Sort != "" {
+		query.Set("sort", o.Sort)
+	}
+	return query, nil
+}
+
+// query returns the URL query with the page number and size, zero values are omitted.
+// It returns ErrorInvalidArgument if a page number or size is negative.
+func (o PageOptions) query() (url.Values, error) {
+	query := url.Values{}
+	switch {
+	case o.LastPage:
+		query.Set("page[number]", "last")
+	case o.PageNumber > 0:
+		query.Set("page[number]", strconv.Itoa(o.PageNumber))
+	case o.PageNumber < 0:
+		return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("PageNumber=%d", o.PageNumber))
+	}
+	switch {
+	case o.PageSize > 0:
+		query.Set("page[size]", strconv.Itoa(o.PageSize))
+	case o.PageSize < 0:
+		return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("PageSize=%d", o.PageSize))
+	}
+	return query, nil
+}
+
+// ListPage retrieves a page of accounts, selected by opts, with the links to the other pages.
+// On success, Accounts of the page is an array of accounts (can be empty, but not nil),
+// otherwise it returns ErrorInvalidArgument for invalid options or an ErrorAPI error that
+// includes the error message, returned by the server.
+func (c *Client) ListPage(opts ListOptions) (*Page, error) {
+	return c.ListPageContext(context.Background(), opts)
+}
+
+// ListPageContext is ListPage with a context that can cancel the request or set its deadline.
+func (c *Client) ListPageContext(ctx context.Context, opts ListOptions) (*Page, error) {
+	query, err := opts.query()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := "/v1/organisation/accounts/"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return c.listEndpoint(ctx, endpoint)
+}
+
+// listEndpoint retrieves the page of accounts at endpoint, the path and query of a List request.
+func (c *Client) listEndpoint(ctx context.Context, endpoint string) (*Page, error) {
+	jResult := data.ResponseDataList{}
+	err := c.get(ctx, &Operation{
+		Name:     "List",
+		Endpoint: endpoint,
+		Response: &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Page{
+		Accounts:   *accountListFromResponse(&jResult),
+		Pagination: pageLinks(jResult.Links),
+	}, nil
+}
+
+// pageLinks parses the links of a page, returned by the server.
+func pageLinks(links data.ResponseLinks) Pagination {
+	return Pagination{Links: PageLinks{
+		Self:  parsePageLink(links.Self),
+		First: parsePageLink(links.First),
+		Last:  parsePageLink(links.Last),
+		Next:  parsePageLink(links.Next),
+		Prev:  parsePageLink(links.Prev),
+	}}
+}
+
+// parsePageLink parses the page number and size from a link, returned by the server, nil if link is empty.
+func parsePageLink(link string) *PageLink {
+	if link == "" {
+		return nil
+	}
+	pageLink := &PageLink{URL: link, Number: -1}
+	u, err := url.Parse(link)
+	if err != nil {
+		return pageLink
+	}
+	query := u.Query()
+	switch number := query.Get("page[number]"); number {
+	case "first":
+		pageLink.Number = 0
+	default:
+		if n, err := strconv.Atoi(number); err == nil {
+			pageLink.Number = n
+		}
+	}
+	if size, err := strconv.Atoi(query.Get("page[size]")); err == nil {
+		pageLink.Size = size
+	}
+	return pageLink
+}
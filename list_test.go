@@ -0,0 +1,98 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestListPage verifies the query of ListOptions and the parsed page links.
+func TestListPage(t *testing.T) {
+	queries := make(chan url.Values, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		acc := generateBasicAccount()
+		recordType := data.Accounts
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&data.ResponseDataList{
+			Data: []data.AccountData{{
+				ID:             acc.ID,
+				OrganisationID: acc.OrganisationID,
+				Type:           &recordType,
+				Version:        &acc.Version,
+				Attributes:     acc.Attributes,
+			}},
+			Links: data.ResponseLinks{
+				Self:  "/v1/organisation/accounts?page%5Bnumber%5D=2&page%5Bsize%5D=10",
+				First: "/v1/organisation/accounts?page%5Bnumber%5D=first&page%5Bsize%5D=10",
+				Last:  "/v1/organisation/accounts?page%5Bnumber%5D=last&page%5Bsize%5D=10",
+				Next:  "/v1/organisation/accounts?page%5Bnumber%5D=3&page%5Bsize%5D=10",
+			},
+		})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	page, err := client.ListPage(account.ListOptions{
+		PageNumber: 2,
+		PageSize:   10,
+		Filter:     account.ListFilter{BankID: "400300", IBAN: "GB11 NWBK"},
+		Sort:       "-created_on",
+	})
+	if err != nil {
+		t.Fatalf("ListPage should succeed: %v", err)
+	}
+	expected := url.Values{
+		"page[number]":    {"2"},
+		"page[size]":      {"10"},
+		"filter[bank_id]": {"400300"},
+		"filter[iban]":    {"GB11 NWBK"},
+		"sort":            {"-created_on"},
+	}
+	if query := <-queries; query.Encode() != expected.Encode() {
+		t.Errorf("Expected query %v, got %v", expected, query)
+	}
+	if len(page.Accounts) != 1 {
+		t.Errorf("Expected 1 account, got %d", len(page.Accounts))
+	}
+	if !page.HasNext() || page.HasPrev() {
+		t.Errorf("Expected a next and no previous page, got %+v", page.Links)
+	}
+	if page.Links.Self.Number != 2 || page.Links.Next.Number != 3 || page.Links.First.Number != 0 ||
+		page.Links.Last.Number != -1 || page.Links.Next.Size != 10 {
+		t.Errorf("Unexpected page numbers or size: self %+v, next %+v, first %+v, last %+v",
+			page.Links.Self, page.Links.Next, page.Links.First, page.Links.Last)
+	}
+
+	// Zero options are omitted, the last page is selected by keyword.
+	if _, err := client.ListPage(account.ListOptions{}); err != nil {
+		t.Fatalf("ListPage should succeed: %v", err)
+	}
+	if query := <-queries; len(query) != 0 {
+		t.Errorf("Expected an empty query, got %v", query)
+	}
+	if _, err := client.ListPage(account.ListOptions{LastPage: true, PageNumber: 5}); err != nil {
+		t.Fatalf("ListPage should succeed: %v", err)
+	}
+	if query := <-queries; query.Get("page[number]") != "last" {
+		t.Errorf("Expected page[number]=last, got %v", query)
+	}
+	// The sentinels of List map to the options.
+	if _, err := client.List(lib.Last, lib.PageSize(5)); err != nil {
+		t.Fatalf("List should succeed: %v", err)
+	}
+	if query := <-queries; query.Get("page[number]") != "last" || query.Get("page[size]") != "5" {
+		t.Errorf("Expected page[number]=last&page[size]=5, got %v", query)
+	}
+
+	for _, opts := range []account.ListOptions{{PageNumber: -1}, {PageSize: -1}} {
+		if _, err := client.ListPage(opts); !lib.IsErrorInvalidArgument(err) {
+			t.Errorf("Expected ErrorInvalidArgument for %+v, got %v", opts, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces the values of redacted fields in logged bodies.
+const RedactedValue = "[REDACTED]"
+
+// redactedFields are the JSON names of data.Attributes fields that identify an account or its holder
+// and must never be logged in clear: AccountNumber, IBAN, BIC, Name, AlternativeNames, SecondaryIdentification
+// and PrivateIdentification.
+var redactedFields = map[string]bool{
+	"account_number":           true,
+	"iban":                     true,
+	"bic":                      true,
+	"name":                     true,
+	"alternative_names":        true,
+	"secondary_identification": true,
+	"private_identification":   true,
+}
+
+// Logger is a structured logger, its methods take a message and alternating keys and values.
+// *slog.Logger implements Logger.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// loggingMiddleware logs every operation: completed operations at info level, failed ones at warn
+// level and request and response bodies, with account identifiers redacted, at debug level.
+func loggingMiddleware(logger Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, op *Operation) error {
+			if op.Body != nil {
+				logger.Debug("account api request body", "operation", op.Name, "body", redactJSON(op.Body))
+			}
+			start := time.Now()
+			err := next(ctx, op)
+			args := []interface{}{
+				"operation", op.Name,
+				"method", op.Method,
+				"endpoint", redactEndpoint(op.Endpoint),
+				"status", op.StatusCode,
+				"attempts", op.Attempts,
+				"duration", time.Since(start),
+			}
+			if err != nil {
+				logger.Warn("account api request failed", append(args, "error", err.Error())...)
+				return err
+			}
+			logger.Info("account api request", args...)
+			if op.Response != nil && op.Method != "DELETE" {
+				if body, err := json.Marshal(op.Response); err == nil {
+					logger.Debug("account api response body", "operation", op.Name, "body", redactJSON(body))
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// redactEndpoint returns endpoint with the values of filter[...] query parameters on redactedFields
+// replaced by RedactedValue, e.g. filter[iban]. Endpoints that can't be parsed are replaced entirely.
+func redactEndpoint(endpoint string) string {
+	i := strings.IndexByte(endpoint, '?')
+	if i < 0 {
+		return endpoint
+	}
+	query, err := url.ParseQuery(endpoint[i+1:])
+	if err != nil {
+		return RedactedValue
+	}
+	redacted := false
+	for key := range query {
+		if strings.HasPrefix(key, "filter[") && strings.HasSuffix(key, "]") && redactedFields[key[len("filter["):len(key)-1]] {
+			query.Set(key, RedactedValue)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return endpoint
+	}
+	return endpoint[:i+1] + query.Encode()
+}
+
+// redactJSON returns body with the values of redactedFields replaced by RedactedValue.
+// Bodies that are not valid JSON are replaced entirely.
+func redactJSON(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return RedactedValue
+	}
+	redacted, err := json.Marshal(redact(v))
+	if err != nil {
+		return RedactedValue
+	}
+	return string(redacted)
+}
+
+// redact replaces the values of redactedFields in a decoded JSON value.
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, field := range t {
+			if redactedFields[k] {
+				t[k] = redactValue(field)
+			} else {
+				t[k] = redact(field)
+			}
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+	return v
+}
+
+// redactValue masks a value, keeping the shape of arrays and empty values.
+func redactValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if t == "" {
+			return t
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redactValue(t[i])
+		}
+		return t
+	}
+	return RedactedValue
+}
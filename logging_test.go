@@ -0,0 +1,94 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testLogger records log messages with their arguments.
+type testLogger struct {
+	mu      sync.Mutex
+	records []string
+}
+
+func (l *testLogger) log(level, msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, fmt.Sprintf("%s %s %v", level, msg, args))
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.log("DEBUG", msg, args...) }
+func (l *testLogger) Info(msg string, args ...interface{})  { l.log("INFO", msg, args...) }
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.log("WARN", msg, args...) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.log("ERROR", msg, args...) }
+
+// TestLogging verifies that operations are logged and account identifiers are redacted from logged bodies
+// and filters.
+func TestLogging(t *testing.T) {
+	acc := generateBasicAccount()
+	acc.Attributes.AccountNumber = "41426819"
+	acc.Attributes.IBAN = "GB11NWBK40030041426819"
+	acc.Attributes.BIC = "NWBKGB22"
+	acc.Attributes.Name = []string{"Samantha Holder"}
+	acc.Attributes.SecondaryIdentification = "A1B2C3D4"
+	acc.Attributes.PrivateIdentification = &data.PrivateIdentification{Identification: "13YH458762"}
+	acc.Attributes.BankID = "400300"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" || r.Method == "GET" {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeAccount(w, http.StatusCreated, acc)
+	}))
+	defer server.Close()
+	logger := &testLogger{}
+	client, err := account.New(account.Config{
+		Server:  server.URL,
+		Timeout: TestTimeout,
+		Logger:  logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Create(acc); err != nil {
+		t.Fatalf("Create should succeed: %v", err)
+	}
+	_ = client.Delete(acc.ID, 0)
+	_, _ = client.ListPage(account.ListOptions{Filter: account.ListFilter{IBAN: acc.Attributes.IBAN, BankID: "400300"}})
+
+	all := strings.Join(logger.records, "\n")
+	for _, secret := range []string{acc.Attributes.AccountNumber, acc.Attributes.IBAN, acc.Attributes.BIC,
+		acc.Attributes.Name[0], acc.Attributes.SecondaryIdentification, acc.Attributes.PrivateIdentification.Identification} {
+		if strings.Contains(all, secret) {
+			t.Errorf("Account identifier %s was logged in clear:\n%s", secret, all)
+		}
+	}
+	if !strings.Contains(all, acc.Attributes.BankID) || !strings.Contains(all, account.RedactedValue) {
+		t.Errorf("Expected bodies with redacted account identifiers, got:\n%s", all)
+	}
+	if len(logger.records) != 6 {
+		t.Fatalf("Expected 6 log records, got %d:\n%s", len(logger.records), all)
+	}
+	expected := []string{
+		"DEBUG account api request body [operation Create",
+		"INFO account api request [operation Create method POST endpoint /v1/organisation/accounts status 201",
+		"DEBUG account api response body [operation Create",
+		"DEBUG account api request body [operation Delete",
+		"WARN account api request failed [operation Delete method DELETE endpoint /v1/organisation/accounts/" + acc.ID.String() + "?version=0 status 404",
+		"WARN account api request failed [operation List method GET endpoint /v1/organisation/accounts/?filter%5Bbank_id%5D=400300&filter%5Biban%5D=%5BREDACTED%5D status 404",
+	}
+	for i, prefix := range expected {
+		if !strings.HasPrefix(logger.records[i], prefix) {
+			t.Errorf("Expected log record starting with '%s', got '%s'", prefix, logger.records[i])
+		}
+	}
+	if !strings.Contains(logger.records[4], "error 404:not found") {
+		t.Errorf("Expected the ErrorAPI message in the log, got '%s'", logger.records[4])
+	}
+}
@@ -0,0 +1,58 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestMetadata verifies that created_on, modified_on and links, returned by the server, are set on accounts.
+func TestMetadata(t *testing.T) {
+	acc := generateBasicAccount()
+	recordType := data.Accounts
+	createdOn := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	modifiedOn := createdOn.Add(time.Hour)
+	accountData := data.AccountData{
+		ID:             acc.ID,
+		OrganisationID: acc.OrganisationID,
+		Type:           &recordType,
+		Version:        &acc.Version,
+		CreatedOn:      &createdOn,
+		ModifiedOn:     &modifiedOn,
+		Attributes:     acc.Attributes,
+	}
+	self := "/v1/organisation/accounts/" + acc.ID.String()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == self {
+			_ = json.NewEncoder(w).Encode(&data.ResponseData{Data: accountData, Links: data.ResponseLinks{Self: self}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&data.ResponseDataList{Data: []data.AccountData{accountData}})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	fetched, err := client.Fetch(acc.ID)
+	if err != nil {
+		t.Fatalf("Fetch should succeed: %v", err)
+	}
+	if !fetched.CreatedOn.Equal(createdOn) || !fetched.ModifiedOn.Equal(modifiedOn) {
+		t.Errorf("Expected created_on %v and modified_on %v, got %v and %v", createdOn, modifiedOn, fetched.CreatedOn, fetched.ModifiedOn)
+	}
+	if fetched.Links == nil || fetched.Links.Self != self {
+		t.Errorf("Expected the self link %s, got %+v", self, fetched.Links)
+	}
+
+	page, err := client.ListPage(account.ListOptions{})
+	if err != nil || len(page.Accounts) != 1 {
+		t.Fatalf("ListPage should return 1 account: %v", err)
+	}
+	if listed := page.Accounts[0]; !listed.ModifiedOn.Equal(modifiedOn) || listed.Links != nil {
+		t.Errorf("Expected modified_on %v and no links in the list, got %v and %+v", modifiedOn, listed.ModifiedOn, listed.Links)
+	}
+}
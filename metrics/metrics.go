@@ -0,0 +1,162 @@
+// Package metrics exposes Prometheus metrics of account client operations.
+package metrics
+
+import (
+	account "accountapi"
+	"accountapi/lib"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector collects metrics of client operations and circuit breaker state changes.
+// It implements prometheus.Collector, so it can be registered in any registry:
+//
+//	collector := metrics.NewCollector("myservice")
+//	registry.MustRegister(collector)
+//	client, err := account.New(account.Config{
+//		Middleware:     []account.Middleware{collector.Middleware()},
+//		CircuitBreaker: account.CircuitBreaker{FailureRate: 0.5, OnStateChange: collector.OnStateChange},
+//	})
+type Collector struct {
+	operations   *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	inFlight     *prometheus.GaugeVec
+	retries      *prometheus.CounterVec
+	breakerState prometheus.Gauge
+	breakerTrans *prometheus.CounterVec
+}
+
+// NewCollector creates the metrics with the namespace, e.g. the name of the calling service.
+func NewCollector(namespace string) *Collector {
+	const subsystem = "account_client"
+	return &Collector{
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "operations_total",
+			Help:      "Number of completed account operations by operation, status code class and error type.",
+		}, []string{"operation", "code", "error"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of account operations, including retries, by operation and status code class.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "code"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "operations_in_flight",
+			Help:      "Number of account operations in flight by operation.",
+		}, []string{"operation"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "retries_total",
+			Help:      "Number of retried requests by operation.",
+		}, []string{"operation"}),
+		breakerState: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "circuit_breaker_state",
+			Help:      "State of the circuit breaker: 0 closed, 1 open, 2 half-open.",
+		}),
+		breakerTrans: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "circuit_breaker_transitions_total",
+			Help:      "Number of circuit breaker state changes by the new state.",
+		}, []string{"state"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.operations.Describe(ch)
+	c.duration.Describe(ch)
+	c.inFlight.Describe(ch)
+	c.retries.Describe(ch)
+	c.breakerState.Describe(ch)
+	c.breakerTrans.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.operations.Collect(ch)
+	c.duration.Collect(ch)
+	c.inFlight.Collect(ch)
+	c.retries.Collect(ch)
+	c.breakerState.Collect(ch)
+	c.breakerTrans.Collect(ch)
+}
+
+// Middleware returns an account.Middleware that measures every operation.
+func (c *Collector) Middleware() account.Middleware {
+	return func(next account.Handler) account.Handler {
+		return func(ctx context.Context, op *account.Operation) error {
+			inFlight := c.inFlight.WithLabelValues(op.Name)
+			inFlight.Inc()
+			start := time.Now()
+			err := next(ctx, op)
+			inFlight.Dec()
+			code := statusClass(op.StatusCode)
+			c.duration.WithLabelValues(op.Name, code).Observe(time.Since(start).Seconds())
+			c.operations.WithLabelValues(op.Name, code, ErrorType(err)).Inc()
+			if op.Attempts > 1 {
+				c.retries.WithLabelValues(op.Name).Add(float64(op.Attempts - 1))
+			}
+			return err
+		}
+	}
+}
+
+// OnStateChange records a circuit breaker state change, it is meant for account.CircuitBreaker.OnStateChange.
+func (c *Collector) OnStateChange(from, to account.BreakerState) {
+	c.breakerState.Set(float64(to))
+	c.breakerTrans.WithLabelValues(to.String()).Inc()
+}
+
+// ErrorType returns the label value for the type of err: "none", "api", "token", "circuit_open",
+// "invalid_argument", "invalid_enum", "signature", "canceled", "timeout", "transport" or "other".
+func ErrorType(err error) string {
+	var errURL *url.Error
+	switch {
+	case err == nil:
+		return "none"
+	case lib.IsErrorAPI(err):
+		return "api"
+	case lib.IsErrorToken(err):
+		return "token"
+	case lib.IsErrorCircuitOpen(err):
+		return "circuit_open"
+	case lib.IsErrorInvalidArgument(err):
+		return "invalid_argument"
+	case lib.IsErrorInvalidEnum(err):
+		return "invalid_enum"
+	case lib.IsErrorSignature(err):
+		return "signature"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &errURL):
+		if errURL.Timeout() {
+			return "timeout"
+		}
+		return "transport"
+	}
+	return "other"
+}
+
+// statusClass returns the class of the status code, e.g. "2xx", or "none" if there was no response.
+func statusClass(statusCode int) string {
+	if statusCode == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
@@ -0,0 +1,90 @@
+package metrics_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/metrics"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCollector verifies operation, retry and circuit breaker metrics registered in a custom registry.
+func TestCollector(t *testing.T) {
+	var requests, down int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&requests, 1) <= 2 || atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_ = json.NewEncoder(w).Encode(data.ErrorMessage{Message: "error"})
+	}))
+	defer server.Close()
+
+	collector := metrics.NewCollector("test")
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collector)
+	client, err := account.New(account.Config{
+		Server:     server.URL,
+		Timeout:    3,
+		Retry:      account.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond},
+		Middleware: []account.Middleware{collector.Middleware()},
+		CircuitBreaker: account.CircuitBreaker{
+			FailureRate:   0.5,
+			MinRequests:   1,
+			CoolDown:      time.Minute,
+			OnStateChange: collector.OnStateChange,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two 503 responses are retried, the third attempt gets 404, the breaker stays closed.
+	_, _ = client.Fetch(uuid.New())
+	// All three attempts get 503, the breaker opens and rejects the next operation.
+	atomic.StoreInt32(&down, 1)
+	_, _ = client.Fetch(uuid.New())
+	_, _ = client.Fetch(uuid.New())
+
+	expected := `
+# HELP test_account_client_operations_total Number of completed account operations by operation, status code class and error type.
+# TYPE test_account_client_operations_total counter
+test_account_client_operations_total{code="4xx",error="api",operation="Fetch"} 1
+test_account_client_operations_total{code="5xx",error="api",operation="Fetch"} 1
+test_account_client_operations_total{code="none",error="circuit_open",operation="Fetch"} 1
+# HELP test_account_client_retries_total Number of retried requests by operation.
+# TYPE test_account_client_retries_total counter
+test_account_client_retries_total{operation="Fetch"} 4
+# HELP test_account_client_operations_in_flight Number of account operations in flight by operation.
+# TYPE test_account_client_operations_in_flight gauge
+test_account_client_operations_in_flight{operation="Fetch"} 0
+# HELP test_account_client_circuit_breaker_state State of the circuit breaker: 0 closed, 1 open, 2 half-open.
+# TYPE test_account_client_circuit_breaker_state gauge
+test_account_client_circuit_breaker_state 1
+# HELP test_account_client_circuit_breaker_transitions_total Number of circuit breaker state changes by the new state.
+# TYPE test_account_client_circuit_breaker_transitions_total counter
+test_account_client_circuit_breaker_transitions_total{state="open"} 1
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"test_account_client_operations_total",
+		"test_account_client_retries_total",
+		"test_account_client_operations_in_flight",
+		"test_account_client_circuit_breaker_state",
+		"test_account_client_circuit_breaker_transitions_total")
+	if err != nil {
+		t.Error(err)
+	}
+	if n := testutil.CollectAndCount(collector, "test_account_client_operation_duration_seconds"); n != 3 {
+		t.Errorf("Expected 3 latency histograms, got %d", n)
+	}
+}
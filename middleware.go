@@ -0,0 +1,45 @@
+package account
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Operation describes a client operation on its way through the middleware chain to the server.
+// Middleware can read and modify the request fields before calling the next handler and the
+// response fields after it returns.
+type Operation struct {
+	Name      string      // Client operation, e.g. "Create", "Fetch", "List", "Update", "Delete", "ListEvents" or "Health".
+	AccountID uuid.UUID   // Account the operation works on, uuid.Nil for operations on more accounts or on other resources.
+	Method    string      // HTTP method.
+	Endpoint  string      // Path and query of the request.
+	Body      []byte      // JSON request body, nil for GET.
+	Header    http.Header // Additional request headers, sent with every attempt.
+	Response  interface{} // Pointer to the value the JSON response is decoded into, e.g. *data.ResponseData.
+
+	StatusCode int // Status code of the last response, 0 if no response was received.
+	Attempts   int // Number of requests sent to the server, including retries.
+
+	ambiguous         bool   // A failed attempt may have been processed by the server.
+	resolvesDuplicate bool   // The caller resolves a 409 after a retried POST, so the POST can be retried.
+	token             string // Bearer token of the last attempt.
+	refreshed         bool   // The request was repeated with a fresh token after 401.
+}
+
+// Handler handles an operation and returns the error of the operation, e.g. ErrorAPI.
+type Handler func(ctx context.Context, op *Operation) error
+
+// Middleware wraps a Handler. It can inspect or modify the operation, call next, and inspect or
+// modify the response and the error. It can short-circuit the operation by returning without
+// calling next, filling in op.Response if the operation should succeed.
+type Middleware func(next Handler) Handler
+
+// chain wraps handler with middleware, the first middleware is the outermost.
+func chain(middleware []Middleware, handler Handler) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
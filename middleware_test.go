@@ -0,0 +1,106 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestMiddleware verifies that middleware sees the operations in order, can modify requests and short-circuit them.
+func TestMiddleware(t *testing.T) {
+	acc := generateBasicAccount()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("X-Tenant") != "test" {
+			writeError(w, http.StatusBadRequest, "missing tenant")
+			return
+		}
+		if r.Method == "DELETE" {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeAccount(w, http.StatusOK, acc)
+	}))
+	defer server.Close()
+
+	trace := []string{}
+	recorder := func(next account.Handler) account.Handler {
+		return func(ctx context.Context, op *account.Operation) error {
+			trace = append(trace, "before "+op.Name)
+			err := next(ctx, op)
+			if op.AccountID != acc.ID {
+				t.Errorf("%s: expected account ID %s, got %s", op.Name, acc.ID, op.AccountID)
+			}
+			if op.Name == "Delete" && (!lib.IsErrorAPI(err) || op.StatusCode != http.StatusNotFound) {
+				t.Errorf("Delete: expected ErrorAPI 404, got %v, status %d", err, op.StatusCode)
+			}
+			trace = append(trace, "after "+op.Name)
+			return err
+		}
+	}
+	tenant := func(next account.Handler) account.Handler {
+		return func(ctx context.Context, op *account.Operation) error {
+			op.Header = http.Header{"X-Tenant": {"test"}}
+			return next(ctx, op)
+		}
+	}
+	cached := map[uuid.UUID]data.ResponseData{}
+	cache := func(next account.Handler) account.Handler {
+		return func(ctx context.Context, op *account.Operation) error {
+			if op.Name != "Fetch" {
+				return next(ctx, op)
+			}
+			response := op.Response.(*data.ResponseData)
+			if r, ok := cached[op.AccountID]; ok {
+				*response = r
+				return nil
+			}
+			err := next(ctx, op)
+			if err == nil {
+				cached[op.AccountID] = *response
+			}
+			return err
+		}
+	}
+	client, err := account.New(account.Config{
+		Server:     server.URL,
+		Timeout:    TestTimeout,
+		Middleware: []account.Middleware{recorder, tenant, cache},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		fetchedAccount, err := client.Fetch(acc.ID)
+		if err != nil {
+			t.Fatalf("Fetch should succeed: %v", err)
+		}
+		if fetchedAccount.ID != acc.ID {
+			t.Errorf("Expected account ID %s, received %s", acc.ID, fetchedAccount.ID)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("The second fetch should be served from the cache, %d requests were sent", requests)
+	}
+	_ = client.Delete(acc.ID, 0)
+
+	expected := []string{"before Fetch", "after Fetch", "before Fetch", "after Fetch", "before Delete", "after Delete"}
+	if len(trace) != len(expected) {
+		t.Fatalf("Expected trace %v, got %v", expected, trace)
+	}
+	for i := range expected {
+		if trace[i] != expected[i] {
+			t.Errorf("Expected trace %v, got %v", expected, trace)
+			break
+		}
+	}
+}
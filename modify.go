@@ -0,0 +1,41 @@
+package account
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DefaultModifyAttempts is the number of read-modify-write attempts of Modify when Config.ModifyAttempts is not set.
+const DefaultModifyAttempts = 5
+
+// Modify fetches the account identified by id, applies mutate to it and updates it with the fetched version.
+// When the update fails with ErrorConflict because the account was changed in the meantime, the account is
+// fetched again and mutate is applied to the new data, up to Config.ModifyAttempts times. mutate can be
+// called more than once and must not have side effects; its error aborts Modify and is returned as is.
+// ID and Version of the account can't be changed by mutate.
+func (c *Client) Modify(id uuid.UUID, mutate func(*data.Account) error) (*data.Account, error) {
+	return c.ModifyContext(context.Background(), id, mutate)
+}
+
+// ModifyContext is Modify with a context that can cancel the requests or set their deadline.
+func (c *Client) ModifyContext(ctx context.Context, id uuid.UUID, mutate func(*data.Account) error) (*data.Account, error) {
+	for attempt := 1; ; attempt++ {
+		account, err := c.FetchContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		version := account.Version
+		if err := mutate(account); err != nil {
+			return nil, err
+		}
+		account.ID = id
+		account.Version = version
+		updated, err := c.UpdateContext(ctx, account)
+		if !lib.IsErrorConflict(err) || attempt >= c.modifyAttempts || ctx.Err() != nil {
+			return updated, err
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// versionedServer serves Fetch and Update of a single account. The first concurrent updates are
+// preceded by a concurrent change of the account, so they fail with 409.
+type versionedServer struct {
+	mu         sync.Mutex
+	stored     *data.Account
+	concurrent int // Number of concurrent changes that cause conflicts.
+	fetches    int
+	updates    int
+}
+
+func (s *versionedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case "GET":
+		s.fetches++
+		writeAccount(w, http.StatusOK, s.stored)
+	case "PATCH":
+		s.updates++
+		if s.concurrent > 0 {
+			s.concurrent--
+			s.stored.Version++
+			s.stored.Attributes.AlternativeNames = append(s.stored.Attributes.AlternativeNames, "concurrent")
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		var req data.RequestUpdate
+		if err := json.Unmarshal(body, &req); err != nil || req.Data.Version == nil {
+			writeError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		if *req.Data.Version != s.stored.Version {
+			writeError(w, http.StatusConflict, "version mismatch")
+			return
+		}
+		s.stored.Version++
+		s.stored.Attributes = req.Data.Attributes
+		writeAccount(w, http.StatusOK, s.stored)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// TestModify verifies that conflicting changes are refetched and the mutation is reapplied.
+func TestModify(t *testing.T) {
+	s := &versionedServer{stored: generateBasicAccount(), concurrent: 2}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, testRetryPolicy)
+
+	calls := 0
+	updated, err := client.Modify(s.stored.ID, func(acc *data.Account) error {
+		calls++
+		acc.Attributes.BankID = "400302"
+		acc.Version = 100 // Ignored, the fetched version is sent.
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Modify should succeed: %v", err)
+	}
+	if calls != 3 || s.fetches != 3 || s.updates != 3 {
+		t.Errorf("Expected 3 mutations, fetches and updates, got %d, %d, %d", calls, s.fetches, s.updates)
+	}
+	if updated.Version != 3 || updated.Attributes.BankID != "400302" || len(updated.Attributes.AlternativeNames) != 2 {
+		t.Errorf("Expected version 3 with both concurrent changes and the new bank ID, got %+v", updated)
+	}
+}
+
+// TestModifyExhausted verifies that Modify gives up with ErrorConflict after Config.ModifyAttempts.
+func TestModifyExhausted(t *testing.T) {
+	s := &versionedServer{stored: generateBasicAccount(), concurrent: 10}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client, err := account.New(account.Config{Server: server.URL, Timeout: TestTimeout, ModifyAttempts: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Modify(s.stored.ID, func(acc *data.Account) error { return nil })
+	if !lib.IsErrorConflict(err) {
+		t.Fatalf("Expected ErrorConflict, got %v", err)
+	}
+	if s.updates != 2 {
+		t.Errorf("Expected 2 updates, got %d", s.updates)
+	}
+
+	errMutate := errors.New("mutate failed")
+	if _, err := client.Modify(s.stored.ID, func(acc *data.Account) error { return errMutate }); err != errMutate {
+		t.Errorf("Expected the error of mutate, got %v", err)
+	}
+	if s.updates != 2 {
+		t.Errorf("Expected no update after a failed mutation, got %d updates", s.updates)
+	}
+}
@@ -0,0 +1,174 @@
+package account
+
+import (
+	"accountapi/lib"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultExpiryDelta is how long before its expiry a token is refreshed when ClientCredentials.ExpiryDelta is not set.
+	DefaultExpiryDelta = 10 * time.Second
+	// DefaultTokenTimeout limits a token request when neither ClientCredentials.HTTPClient nor Config.Timeout is set.
+	DefaultTokenTimeout = 30 * time.Second
+)
+
+// TokenSource provides bearer tokens that authenticate requests to the account API.
+// Token endpoint failures should be returned as ErrorToken, so that they aren't taken for failures
+// of the account API by the retries, the concurrency limit and the circuit breaker.
+type TokenSource interface {
+	// Token returns a valid access token.
+	Token(ctx context.Context) (string, error)
+	// Invalidate discards token if it is still cached, after the server has rejected it.
+	Invalidate(token string)
+}
+
+// ClientCredentials is a TokenSource that obtains tokens from an OAuth2 token endpoint with
+// the client credentials grant. Tokens are cached and refreshed ExpiryDelta before they expire.
+// Concurrent callers share a single token request, each of them stops waiting for it when its context is done.
+type ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+	TokenURL     string
+	Scopes       []string
+	ExpiryDelta  time.Duration // Defaults to DefaultExpiryDelta.
+	HTTPClient   *http.Client  // Client for the token endpoint, defaults to a client with the Config.Timeout of the account client.
+
+	mu      sync.Mutex
+	token   string
+	expiry  time.Time
+	refresh *tokenRefresh // Token request in flight, nil if there is none.
+	timeout time.Duration // Timeout of the default HTTP client, set by New.
+}
+
+// tokenRefresh is a token request, shared by the callers that need a new token while it is in flight.
+type tokenRefresh struct {
+	done  chan struct{} // Closed when the request has completed.
+	token string
+	err   error
+}
+
+// tokenResponse is returned by the token endpoint.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Token returns the cached token or requests a new one if the cached token is about to expire.
+// It returns the error of ctx if ctx is done before the token is received.
+func (cc *ClientCredentials) Token(ctx context.Context) (string, error) {
+	cc.mu.Lock()
+	expiryDelta := cc.ExpiryDelta
+	if expiryDelta <= 0 {
+		expiryDelta = DefaultExpiryDelta
+	}
+	if cc.token != "" && (cc.expiry.IsZero() || time.Now().Add(expiryDelta).Before(cc.expiry)) {
+		token := cc.token
+		cc.mu.Unlock()
+		return token, nil
+	}
+	refresh := cc.refresh
+	if refresh == nil {
+		refresh = &tokenRefresh{done: make(chan struct{})}
+		cc.refresh = refresh
+		go cc.requestRefresh(refresh)
+	}
+	cc.mu.Unlock()
+	select {
+	case <-refresh.done:
+		return refresh.token, refresh.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// requestRefresh requests a new token for refresh and caches it. The request is not cancelled by the
+// callers, as it is shared, it is limited by the timeout of the HTTP client.
+func (cc *ClientCredentials) requestRefresh(refresh *tokenRefresh) {
+	token, expiry, err := cc.requestToken(context.Background())
+	cc.mu.Lock()
+	if err == nil {
+		cc.token, cc.expiry = token, expiry
+	}
+	cc.refresh = nil
+	cc.mu.Unlock()
+	refresh.token, refresh.err = token, err
+	close(refresh.done)
+}
+
+// setTimeout sets the timeout of the default HTTP client, if it wasn't set by another account client.
+func (cc *ClientCredentials) setTimeout(timeout time.Duration) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.timeout == 0 {
+		cc.timeout = timeout
+	}
+}
+
+// Invalidate ...
+func (cc *ClientCredentials) Invalidate(token string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.token == token {
+		cc.token = ""
+	}
+}
+
+// requestToken requests a new token from the token endpoint. Token endpoint errors are returned as ErrorToken.
+func (cc *ClientCredentials) requestToken(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(cc.Scopes) > 0 {
+		form.Set("scope", strings.Join(cc.Scopes, " "))
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", cc.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(cc.ClientID), url.QueryEscape(cc.ClientSecret))
+	httpClient := cc.HTTPClient
+	if httpClient == nil {
+		cc.mu.Lock()
+		timeout := cc.timeout
+		cc.mu.Unlock()
+		if timeout <= 0 {
+			timeout = DefaultTokenTimeout
+		}
+		httpClient = &http.Client{Timeout: timeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", time.Time{}, err
+		}
+		return "", time.Time{}, lib.NewErrorToken(0, err.Error())
+	}
+	defer resp.Body.Close()
+	jResult := tokenResponse{}
+	err = json.NewDecoder(resp.Body).Decode(&jResult)
+	if isErrorStatus(resp.StatusCode) {
+		if err != nil {
+			return "", time.Time{}, lib.NewErrorToken(resp.StatusCode, err.Error())
+		}
+		return "", time.Time{}, lib.NewErrorToken(resp.StatusCode, strings.TrimSpace(jResult.Error+" "+jResult.ErrorDescription))
+	}
+	if err != nil {
+		return "", time.Time{}, lib.NewErrorToken(resp.StatusCode, err.Error())
+	}
+	if jResult.AccessToken == "" {
+		return "", time.Time{}, lib.NewErrorToken(resp.StatusCode, "token endpoint returned no access_token")
+	}
+	expiry := time.Time{}
+	if jResult.ExpiresIn > 0 {
+		expiry = time.Now().Add(time.Duration(jResult.ExpiresIn) * time.Second)
+	}
+	return jResult.AccessToken, expiry, nil
+}
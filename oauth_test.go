@@ -0,0 +1,222 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	TestClientID     = "test-client"
+	TestClientSecret = "test-secret"
+)
+
+// testTokenServer is a stand-in OAuth2 token endpoint that issues numbered tokens.
+type testTokenServer struct {
+	mu        sync.Mutex
+	issued    int
+	expiresIn int
+}
+
+func (s *testTokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != TestClientID || secret != TestClientSecret || r.FormValue("grant_type") != "client_credentials" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+	s.mu.Lock()
+	s.issued++
+	token := fmt.Sprintf("token-%d", s.issued)
+	expiresIn := s.expiresIn
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": token,
+		"token_type":   "bearer",
+		"expires_in":   expiresIn,
+	})
+}
+
+func (s *testTokenServer) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("token-%d", s.issued)
+}
+
+// TestClientCredentials verifies that bearer tokens are attached, cached, refreshed before expiry and after 401.
+func TestClientCredentials(t *testing.T) {
+	tokenServer := &testTokenServer{expiresIn: 3600}
+	tokenEndpoint := httptest.NewServer(tokenServer)
+	defer tokenEndpoint.Close()
+	acc := generateBasicAccount()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+tokenServer.current() {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		writeAccount(w, http.StatusOK, acc)
+	}))
+	defer server.Close()
+	credentials := &account.ClientCredentials{
+		ClientID:     TestClientID,
+		ClientSecret: TestClientSecret,
+		TokenURL:     tokenEndpoint.URL,
+	}
+	client, err := account.New(account.Config{
+		Server:      server.URL,
+		Timeout:     TestTimeout,
+		TokenSource: credentials,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Fetch(acc.ID); err != nil {
+			t.Errorf("Fetch with a bearer token should succeed: %v", err)
+		}
+	}
+	if tokenServer.issued != 1 {
+		t.Errorf("Expected a cached token, %d tokens were issued", tokenServer.issued)
+	}
+
+	// The server rejects the cached token, the client retries once with a fresh one.
+	tokenServer.mu.Lock()
+	tokenServer.issued++
+	tokenServer.mu.Unlock()
+	if _, err := client.Fetch(acc.ID); err != nil {
+		t.Errorf("Fetch should succeed with a refreshed token: %v", err)
+	}
+	if tokenServer.issued != 3 {
+		t.Errorf("Expected a refreshed token after 401, %d tokens were issued", tokenServer.issued)
+	}
+
+	// Tokens that expire within ExpiryDelta are refreshed before they are used.
+	tokenServer.mu.Lock()
+	tokenServer.expiresIn = 1
+	tokenServer.mu.Unlock()
+	credentials.Invalidate(tokenServer.current())
+	for i := 0; i < 2; i++ {
+		if _, err := client.Fetch(acc.ID); err != nil {
+			t.Errorf("Fetch with a bearer token should succeed: %v", err)
+		}
+	}
+	if tokenServer.issued != 5 {
+		t.Errorf("Expected tokens to be refreshed before expiry, %d tokens were issued", tokenServer.issued)
+	}
+
+	// Token endpoint errors are returned as ErrorToken and the request isn't repeated after its 401.
+	wrongCredentials := &account.ClientCredentials{
+		ClientID:     TestClientID,
+		ClientSecret: "wrong",
+		TokenURL:     tokenEndpoint.URL,
+		ExpiryDelta:  time.Second,
+	}
+	if _, err := wrongCredentials.Token(context.Background()); err == nil {
+		t.Error("Invalid client credentials should fail")
+	}
+	client, _ = account.New(account.Config{Server: server.URL, Timeout: TestTimeout, TokenSource: wrongCredentials})
+	if _, err := client.Fetch(acc.ID); !lib.IsErrorToken(err) || err.(*lib.ErrorToken).StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected ErrorToken 401 from the token endpoint, got: %v", err)
+	}
+}
+
+// TestTokenEndpointFailure verifies that token endpoint failures don't reduce the concurrency limit or open the circuit breaker.
+func TestTokenEndpointFailure(t *testing.T) {
+	var tokenRequests int32
+	tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "temporarily_unavailable"})
+	}))
+	defer tokenEndpoint.Close()
+	acc := generateBasicAccount()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeAccount(w, http.StatusOK, acc)
+	}))
+	defer server.Close()
+	client, err := account.New(account.Config{
+		Server:         server.URL,
+		Timeout:        TestTimeout,
+		Retry:          account.RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond},
+		Concurrency:    account.Concurrency{Max: 8},
+		CircuitBreaker: account.CircuitBreaker{FailureRate: 0.5, MinRequests: 2},
+		TokenSource:    &account.ClientCredentials{ClientID: TestClientID, ClientSecret: TestClientSecret, TokenURL: tokenEndpoint.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.Fetch(acc.ID); !lib.IsErrorToken(err) || err.(*lib.ErrorToken).StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Expected ErrorToken 503 from the token endpoint, got: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&tokenRequests); n != 4 {
+		t.Errorf("Expected one token request per Fetch, got %d", n)
+	}
+	if limit := client.ConcurrencyLimit(); limit != 8 {
+		t.Errorf("Token endpoint failures shouldn't reduce the concurrency limit, got %d", limit)
+	}
+	if state := client.BreakerState(); state != account.BreakerClosed {
+		t.Errorf("Token endpoint failures shouldn't open the circuit breaker, got %s", state)
+	}
+}
+
+// TestTokenEndpointHanging verifies that callers waiting for a token stop waiting when their context is done
+// and that the shared token request is limited by Config.Timeout.
+func TestTokenEndpointHanging(t *testing.T) {
+	release := make(chan struct{})
+	tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer tokenEndpoint.Close()
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(data.HealthResponse{Status: "up"})
+	}))
+	defer server.Close()
+	client, err := account.New(account.Config{
+		Server:      server.URL,
+		Timeout:     1,
+		TokenSource: &account.ClientCredentials{ClientID: TestClientID, ClientSecret: TestClientSecret, TokenURL: tokenEndpoint.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	background := make(chan bool)
+	go func() { background <- client.Health() }()
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if client.HealthContext(ctx) {
+		t.Error("Health should fail without a token")
+	}
+	if elapsed := time.Since(start); elapsed > 600*time.Millisecond {
+		t.Errorf("HealthContext should stop waiting for the token at its deadline, it took %v", elapsed)
+	}
+	select {
+	case up := <-background:
+		if up {
+			t.Error("Health should fail without a token")
+		}
+	case <-time.After(3 * time.Second):
+		t.Error("The token request should time out with Config.Timeout")
+	}
+}
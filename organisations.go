@@ -0,0 +1,132 @@
+package account
+
+import (
+	"accountapi/data"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Organisations manages the organisation units that own the accounts.
+type Organisations struct {
+	c *Client
+}
+
+// Organisations returns the client of the organisation units, its requests are sent like the
+// requests of c, with the same retries, limits and middleware.
+func (c *Client) Organisations() *Organisations {
+	return &Organisations{c: c}
+}
+
+// OrganisationPage is a page of organisations with links to the neighbouring pages.
+type OrganisationPage struct {
+	Organisations []data.Organisation
+	Pagination
+}
+
+// Create creates an organisation unit with the ID and attributes of organisation. On success, it returns
+// the organisation data, returned by the server, otherwise it returns an ErrorAPI error that includes
+// the error message, returned by the server. Create is not retried, as a retried create that is answered
+// with 409 can't be told apart from a duplicate.
+func (o *Organisations) Create(organisation *data.Organisation) (*data.Organisation, error) {
+	return o.CreateContext(context.Background(), organisation)
+}
+
+// CreateContext is Create with a context that can cancel the request or set its deadline.
+func (o *Organisations) CreateContext(ctx context.Context, organisation *data.Organisation) (*data.Organisation, error) {
+	requestType := data.Organisations
+	jResult := data.ResponseOrganisation{}
+	bin, err := json.Marshal(&data.RequestCreateOrganisation{
+		Data: data.OrganisationData{
+			ID:         organisation.ID,
+			Type:       &requestType,
+			Attributes: organisation.Attributes,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = o.c.post(ctx, &Operation{
+		Name:     "CreateOrganisation",
+		Endpoint: "/v1/organisation/units",
+		Body:     bin,
+		Response: &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return organisationFromData(&jResult.Data), nil
+}
+
+// Fetch fetches an organisation unit by id. On success, it returns the organisation data, returned by
+// the server, otherwise it returns an ErrorAPI error that includes the error message, returned by the server.
+func (o *Organisations) Fetch(id uuid.UUID) (*data.Organisation, error) {
+	return o.FetchContext(context.Background(), id)
+}
+
+// FetchContext is Fetch with a context that can cancel the request or set its deadline.
+func (o *Organisations) FetchContext(ctx context.Context, id uuid.UUID) (*data.Organisation, error) {
+	jResult := data.ResponseOrganisation{}
+	err := o.c.get(ctx, &Operation{
+		Name:     "FetchOrganisation",
+		Endpoint: fmt.Sprintf("%s%s", "/v1/organisation/units/", id.String()),
+		Response: &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return organisationFromData(&jResult.Data), nil
+}
+
+// List retrieves a page of organisation units, selected by opts. On success, Organisations of the page is
+// an array of organisations (can be empty, but not nil), otherwise it returns ErrorInvalidArgument for
+// invalid options or an ErrorAPI error that includes the error message, returned by the server.
+func (o *Organisations) List(opts PageOptions) (*OrganisationPage, error) {
+	return o.ListContext(context.Background(), opts)
+}
+
+// ListContext is List with a context that can cancel the request or set its deadline.
+func (o *Organisations) ListContext(ctx context.Context, opts PageOptions) (*OrganisationPage, error) {
+	query, err := opts.query()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := "/v1/organisation/units"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	jResult := data.ResponseOrganisationList{}
+	err = o.c.get(ctx, &Operation{
+		Name:     "ListOrganisations",
+		Endpoint: endpoint,
+		Response: &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	organisations := []data.Organisation{}
+	for i := range jResult.Data {
+		organisations = append(organisations, *organisationFromData(&jResult.Data[i]))
+	}
+	return &OrganisationPage{Organisations: organisations, Pagination: pageLinks(jResult.Links)}, nil
+}
+
+// organisationFromData copies the values from organisation data into Organisation structure.
+func organisationFromData(d *data.OrganisationData) *data.Organisation {
+	organisation := &data.Organisation{
+		ID:         d.ID,
+		Attributes: d.Attributes,
+	}
+	if d.Version != nil {
+		organisation.Version = *d.Version
+	}
+	if d.CreatedOn != nil {
+		organisation.CreatedOn = *d.CreatedOn
+	}
+	if d.ModifiedOn != nil {
+		organisation.ModifiedOn = *d.ModifiedOn
+	}
+	return organisation
+}
@@ -0,0 +1,91 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestOrganisations verifies creating, fetching and listing organisation units.
+func TestOrganisations(t *testing.T) {
+	var mu sync.Mutex
+	stored := []data.OrganisationData{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/organisation/units":
+			var req data.RequestCreateOrganisation
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Data.Type == nil || *req.Data.Type != data.Organisations {
+				writeError(w, http.StatusBadRequest, "invalid request")
+				return
+			}
+			version := 0
+			req.Data.Version = &version
+			stored = append(stored, req.Data)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&data.ResponseOrganisation{Data: req.Data})
+		case r.Method == "GET" && r.URL.Path == "/v1/organisation/units":
+			if r.URL.Query().Get("page[size]") != "10" {
+				writeError(w, http.StatusBadRequest, "invalid page size")
+				return
+			}
+			_ = json.NewEncoder(w).Encode(&data.ResponseOrganisationList{Data: stored})
+		default:
+			for _, organisation := range stored {
+				if r.URL.Path == "/v1/organisation/units/"+organisation.ID.String() {
+					_ = json.NewEncoder(w).Encode(&data.ResponseOrganisation{Data: organisation})
+					return
+				}
+			}
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	}))
+	defer server.Close()
+	organisations := newTestClient(t, server, account.RetryPolicy{}).Organisations()
+
+	organisation := &data.Organisation{ID: uuid.New(), Attributes: data.OrganisationAttributes{Name: "Tenant 1"}}
+	created, err := organisations.Create(organisation)
+	if err != nil || created.ID != organisation.ID || created.Attributes.Name != "Tenant 1" {
+		t.Fatalf("Create should return the organisation, got %+v, %v", created, err)
+	}
+	fetched, err := organisations.Fetch(organisation.ID)
+	if err != nil || fetched.ID != organisation.ID {
+		t.Errorf("Fetch should return the organisation, got %+v, %v", fetched, err)
+	}
+	if _, err := organisations.Fetch(uuid.New()); !lib.IsErrorAPI(err) {
+		t.Errorf("Expected ErrorAPI for a missing organisation, got %v", err)
+	}
+	page, err := organisations.List(account.PageOptions{PageSize: 10})
+	if err != nil || len(page.Organisations) != 1 || page.Organisations[0].Attributes.Name != "Tenant 1" || page.HasNext() {
+		t.Errorf("List should return the organisation, got %+v, %v", page, err)
+	}
+}
+
+// TestOrganisationCreateNotRetried verifies that an organisation create, which could be a duplicate, is not retried.
+func TestOrganisationCreateNotRetried(t *testing.T) {
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&posts, 1) == 1 {
+			writeError(w, http.StatusServiceUnavailable, "unavailable")
+			return
+		}
+		writeError(w, http.StatusConflict, "duplicate")
+	}))
+	defer server.Close()
+	organisations := newTestClient(t, server, testRetryPolicy).Organisations()
+
+	_, err := organisations.Create(&data.Organisation{ID: uuid.New(), Attributes: data.OrganisationAttributes{Name: "Tenant 1"}})
+	if !isStatus(err, http.StatusServiceUnavailable) || atomic.LoadInt32(&posts) != 1 {
+		t.Errorf("Expected the 503 of a single attempt, got %v after %d attempts", err, posts)
+	}
+}
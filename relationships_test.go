@@ -0,0 +1,52 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestRelationships verifies that relationships and private identification are sent with Create and returned by Fetch.
+func TestRelationships(t *testing.T) {
+	var stored data.AccountData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var req data.RequestCreate
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			stored = req.Data
+			w.WriteHeader(http.StatusCreated)
+		}
+		version := 0
+		stored.Version = &version
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&data.ResponseData{Data: stored})
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	acc := generateBasicAccount()
+	acc.Attributes.PrivateIdentification = &data.PrivateIdentification{BirthDate: "2017-07-23", City: "London"}
+	acc.Relationships = &data.Relationships{
+		AccountEvents: &data.Relationship{Data: []data.ResourceIdentifier{{Type: data.AccountEvents, ID: uuid.New()}}},
+	}
+	if _, err := client.Create(acc); err != nil {
+		t.Fatalf("Create should succeed: %v", err)
+	}
+	if !reflect.DeepEqual(stored.Relationships, acc.Relationships) {
+		t.Errorf("Expected the relationships sent with Create, got %+v", stored.Relationships)
+	}
+	fetched, err := client.Fetch(acc.ID)
+	if err != nil {
+		t.Fatalf("Fetch should succeed: %v", err)
+	}
+	if !reflect.DeepEqual(fetched.Relationships, acc.Relationships) ||
+		!reflect.DeepEqual(fetched.Attributes.PrivateIdentification, acc.Attributes.PrivateIdentification) {
+		t.Errorf("Expected the relationships and private identification from Fetch, got %+v", fetched)
+	}
+}
@@ -0,0 +1,166 @@
+package account
+
+import (
+	"accountapi/lib"
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultBaseBackoff is used when RetryPolicy enables retries without setting BaseBackoff.
+	DefaultBaseBackoff = 100 * time.Millisecond
+	// DefaultMaxBackoff is used when RetryPolicy enables retries without setting MaxBackoff.
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// RetryPolicy defines how requests that failed with a transient error are retried.
+// Transient errors are communication errors (connection reset, timeout, ...) and
+// 429, 500, 502, 503 and 504 responses. Only safe requests are retried: GET always,
+// DELETE because it always carries the account version and the account create POST
+// because a duplicate create is answered with 409 and resolved by comparing the stored
+// account. Other POST requests are not retried.
+// The zero value disables retries.
+type RetryPolicy struct {
+	MaxAttempts int           // Maximum number of attempts including the first one, values below 2 disable retries.
+	BaseBackoff time.Duration // Backoff before the first retry, doubled before every following retry.
+	MaxBackoff  time.Duration // Upper limit of a single backoff; a longer Retry-After stops retrying.
+	Jitter      float64       // Fraction of the backoff (0-1) that is randomly subtracted to spread retries.
+}
+
+// withDefaults fills in the backoff durations that were not set.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.BaseBackoff <= 0 {
+		p.BaseBackoff = DefaultBaseBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	} else if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	return p
+}
+
+// backoff returns the delay before the retry that follows attempt, or false if
+// the server asked for a longer delay than MaxBackoff.
+func (p RetryPolicy) backoff(attempt int, err error) (time.Duration, bool) {
+	d := p.BaseBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	if errAPI, ok := lib.ErrorCauser(err).(*lib.ErrorAPI); ok && errAPI.RetryAfter > d {
+		if errAPI.RetryAfter > p.MaxBackoff {
+			return 0, false
+		}
+		d = errAPI.RetryAfter
+	}
+	return d, true
+}
+
+// send sends the request with doRequest and retries failed attempts according to the client's RetryPolicy.
+// Every attempt is subject to the client's rate and concurrency limits.
+// A request rejected with 401 is repeated once with a fresh token from the client's TokenSource.
+// A retried DELETE that finds no account after an ambiguous attempt is treated as successful.
+func (c *Client) send(ctx context.Context, op *Operation) error {
+	for {
+		err := c.attempt(ctx, op)
+		if err == nil {
+			return nil
+		}
+		if c.tokens != nil && !op.refreshed && isStatus(err, http.StatusUnauthorized) {
+			c.tokens.Invalidate(op.token)
+			op.refreshed = true
+			continue
+		}
+		if op.Method == "DELETE" && op.ambiguous && isStatus(err, http.StatusNotFound) {
+			return nil // An earlier attempt has deleted the account.
+		}
+		if ctx.Err() != nil || !isRetryable(op) || !isTransient(err) || op.Attempts >= c.retry.MaxAttempts {
+			return err
+		}
+		wait, ok := c.retry.backoff(op.Attempts, err)
+		if !ok {
+			return err
+		}
+		if !isStatus(err, http.StatusTooManyRequests) {
+			op.ambiguous = true
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once, when the rate and concurrency limits allow it.
+func (c *Client) attempt(ctx context.Context, op *Operation) error {
+	if err := c.rateLimit.wait(ctx); err != nil {
+		return err
+	}
+	if err := c.concurrency.acquire(ctx); err != nil {
+		return err
+	}
+	op.Attempts++
+	err := c.doRequest(ctx, op)
+	c.concurrency.release(err)
+	return err
+}
+
+// isRetryable returns true for requests that are safe to retry.
+func isRetryable(op *Operation) bool {
+	switch op.Method {
+	case "GET", "DELETE":
+		return true
+	case "POST":
+		return op.resolvesDuplicate
+	}
+	return false
+}
+
+// isTransient returns true for errors that may not repeat when the request is retried.
+func isTransient(err error) bool {
+	if errAPI, ok := lib.ErrorCauser(err).(*lib.ErrorAPI); ok {
+		switch errAPI.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var errURL *url.Error
+	return errors.As(err, &errURL)
+}
+
+// parseRetryAfter parses the Retry-After header that is either a number of seconds or an HTTP date.
+// It returns 0 if the header is missing or invalid.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
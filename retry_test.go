@@ -0,0 +1,191 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient creates a client for a local httptest server.
+func newTestClient(t *testing.T, server *httptest.Server, retry account.RetryPolicy) *account.Client {
+	client, err := account.New(account.Config{
+		Server:  server.URL,
+		Timeout: TestTimeout,
+		Retry:   retry,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// writeAccount writes acc as the server response data.
+func writeAccount(w http.ResponseWriter, status int, acc *data.Account) {
+	recordType := data.Accounts
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&data.ResponseData{
+		Data: data.AccountData{
+			ID:             acc.ID,
+			OrganisationID: acc.OrganisationID,
+			Type:           &recordType,
+			Version:        &acc.Version,
+			Attributes:     acc.Attributes,
+		},
+	})
+}
+
+// writeError writes an error response with the error_message.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data.ErrorMessage{Message: message})
+}
+
+var testRetryPolicy = account.RetryPolicy{
+	MaxAttempts: 3,
+	BaseBackoff: time.Millisecond,
+	MaxBackoff:  50 * time.Millisecond,
+	Jitter:      0.5,
+}
+
+// TestRetryFetch verifies that transient errors are retried and Retry-After is honoured.
+func TestRetryFetch(t *testing.T) {
+	acc := generateBasicAccount()
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&attempts, 1) {
+		case 1:
+			writeError(w, http.StatusBadGateway, "bad gateway")
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			writeError(w, http.StatusTooManyRequests, "slow down")
+		default:
+			writeAccount(w, http.StatusOK, acc)
+		}
+	}))
+	defer server.Close()
+
+	fetchedAccount, err := newTestClient(t, server, testRetryPolicy).Fetch(acc.ID)
+	if err != nil {
+		t.Fatalf("Fetch should succeed after retries: %v", err)
+	}
+	if fetchedAccount.ID != acc.ID {
+		t.Errorf("Expected account ID %s, received %s", acc.ID, fetchedAccount.ID)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+// TestRetryExhausted verifies that the last error is returned when all attempts fail and
+// that errors that are not transient are not retried.
+func TestRetryExhausted(t *testing.T) {
+	var attempts int32
+	status := int32(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		writeError(w, int(atomic.LoadInt32(&status)), "unavailable")
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, testRetryPolicy)
+
+	_, err := client.Fetch(generateBasicAccount().ID)
+	if !lib.IsErrorAPI(err) || err.(*lib.ErrorAPI).StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected ErrorAPI 503, got: %v", err)
+	}
+	if attempts != int32(testRetryPolicy.MaxAttempts) {
+		t.Errorf("Expected %d attempts, got %d", testRetryPolicy.MaxAttempts, attempts)
+	}
+
+	atomic.StoreInt32(&attempts, 0)
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	_, err = client.Fetch(generateBasicAccount().ID)
+	if !lib.IsErrorAPI(err) {
+		t.Errorf("Expected ErrorAPI 404, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Errors that are not transient should not be retried, got %d attempts", attempts)
+	}
+
+	// Retry-After beyond MaxBackoff stops retrying.
+	atomic.StoreInt32(&attempts, 0)
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "60")
+		writeError(w, http.StatusTooManyRequests, "slow down")
+	}))
+	defer slowServer.Close()
+	_, err = newTestClient(t, slowServer, testRetryPolicy).Fetch(generateBasicAccount().ID)
+	if !lib.IsErrorAPI(err) || err.(*lib.ErrorAPI).RetryAfter != time.Minute {
+		t.Errorf("Expected ErrorAPI 429 with Retry-After, got: %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("Retry-After beyond MaxBackoff should not be retried, got %d attempts", attempts)
+	}
+}
+
+// TestRetryCreateConflict verifies that a retried create, answered with 409, succeeds when the stored account is identical.
+func TestRetryCreateConflict(t *testing.T) {
+	acc := generateBasicAccount()
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET":
+			writeAccount(w, http.StatusOK, acc)
+		case atomic.AddInt32(&attempts, 1) == 1:
+			writeError(w, http.StatusBadGateway, "bad gateway") // The account was created, but the response was lost.
+		default:
+			writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		}
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, testRetryPolicy)
+
+	createdAccount, err := client.Create(acc)
+	if err != nil {
+		t.Fatalf("Retried create of an identical account should succeed: %v", err)
+	}
+	if createdAccount.ID != acc.ID {
+		t.Errorf("Expected account ID %s, received %s", acc.ID, createdAccount.ID)
+	}
+
+	// A duplicate without an earlier ambiguous attempt is an error.
+	_, err = client.Create(acc)
+	if !lib.IsErrorAPI(err) || err.(*lib.ErrorAPI).StatusCode != http.StatusConflict {
+		t.Errorf("Expected ErrorAPI 409, got: %v", err)
+	}
+
+	// A different stored account is an error.
+	atomic.StoreInt32(&attempts, 0)
+	other := *acc
+	other.Attributes.BankID = "OTHERBANK"
+	_, err = client.Create(&other)
+	if !lib.IsErrorAPI(err) || err.(*lib.ErrorAPI).StatusCode != http.StatusConflict {
+		t.Errorf("Expected ErrorAPI 409 for a different account, got: %v", err)
+	}
+}
+
+// TestRetryDelete verifies that a retried delete, answered with 404, succeeds.
+func TestRetryDelete(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			writeError(w, http.StatusInternalServerError, "server_error")
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := newTestClient(t, server, testRetryPolicy).Delete(generateBasicAccount().ID, 0)
+	if err != nil {
+		t.Errorf("Retried delete of a deleted account should succeed: %v", err)
+	}
+}
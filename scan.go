@@ -0,0 +1,261 @@
+package account
+
+import (
+	"accountapi/data"
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DefaultScanWorkers is the number of pages fetched concurrently by Scan when ScanOptions.Workers is not set.
+const DefaultScanWorkers = 4
+
+// ScanOptions configures Scan.
+type ScanOptions struct {
+	PageSize  int        // Number of accounts on a page, the server default (1000) if 0.
+	Filter    ListFilter // Only accounts that match the filter are scanned.
+	Workers   int        // Number of pages fetched concurrently, DefaultScanWorkers if not set.
+	Unordered bool       // Deliver the pages as they are fetched instead of in page order.
+}
+
+// ScanReport describes a completed or aborted scan. Accounts that are created or deleted during the
+// scan shift the following accounts between pages: an account that moved to a page that was not
+// fetched yet is delivered again and reported as a duplicate, an account that moved to a page that was
+// already fetched is missed and a page that lost accounts is reported as short. When the scan finds
+// shifted accounts or the last page changed during the scan, the pages are listed once more and the
+// accounts that were missed are delivered after the others and reported by ID.
+type ScanReport struct {
+	Pages      int         // Number of delivered pages.
+	Accounts   int         // Number of accounts delivered to the callback.
+	Duplicates []uuid.UUID // Accounts that were found on more than one page, they were delivered once.
+	ShortPages []int       // Numbers of pages, before the last one, with fewer accounts than the page size.
+	Missed     []uuid.UUID // Accounts that were missed by the scan and found when the pages were listed again.
+	Relisted   bool        // The pages were listed again to find the missed accounts.
+}
+
+// Consistent returns true if no accounts were found to shift between pages during the scan.
+func (r *ScanReport) Consistent() bool {
+	return len(r.Duplicates) == 0 && len(r.ShortPages) == 0 && len(r.Missed) == 0
+}
+
+// scanner delivers the accounts of the scanned pages, skipping duplicates.
+type scanner struct {
+	report    ScanReport
+	seen      map[uuid.UUID]bool
+	pageSize  int  // Expected number of accounts on a page, 0 if unknown.
+	last      int  // Number of the last page.
+	relisting bool // The pages are listed again, only the accounts that were not seen yet are reported.
+	fn        func(*data.Account) error
+}
+
+// deliver passes the accounts of the page number to the callback.
+func (s *scanner) deliver(number int, page *Page) error {
+	if !s.relisting {
+		s.report.Pages++
+		if number < s.last && s.pageSize > 0 && len(page.Accounts) < s.pageSize {
+			s.report.ShortPages = append(s.report.ShortPages, number)
+		}
+	}
+	for i := range page.Accounts {
+		acc := &page.Accounts[i]
+		if s.seen[acc.ID] {
+			if !s.relisting {
+				s.report.Duplicates = append(s.report.Duplicates, acc.ID)
+			}
+			continue
+		}
+		s.seen[acc.ID] = true
+		s.report.Accounts++
+		if s.relisting {
+			s.report.Missed = append(s.report.Missed, acc.ID)
+		}
+		if err := s.fn(acc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scan fetches all accounts and passes them to fn. It fetches the last page to find the number of pages,
+// then it fetches the other pages with opts.Workers concurrent requests and passes their accounts to fn
+// in page order or, if opts.Unordered is set, in the order the pages arrive. fn is never called
+// concurrently; its error aborts the scan and is returned. Pages are fetched at most two per worker ahead
+// of fn, so the memory use doesn't depend on the number of accounts. If the server doesn't return the
+// page number of the last page, the pages are fetched one after another following the next links.
+// At the end, the last page is fetched again; if it changed or accounts shifted between pages, all
+// pages are listed once more and the accounts that were missed are passed to fn.
+// The returned report lists the accounts that shifted between pages during the scan.
+func (c *Client) Scan(opts ScanOptions, fn func(*data.Account) error) (*ScanReport, error) {
+	return c.ScanContext(context.Background(), opts, fn)
+}
+
+// ScanContext is Scan with a context that can cancel the requests or set their deadline.
+func (c *Client) ScanContext(ctx context.Context, opts ScanOptions, fn func(*data.Account) error) (*ScanReport, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultScanWorkers
+	}
+	last, err := c.ListPageContext(ctx, ListOptions{LastPage: true, PageSize: opts.PageSize, Filter: opts.Filter})
+	if err != nil {
+		return &ScanReport{}, err
+	}
+	s := &scanner{seen: map[uuid.UUID]bool{}, pageSize: opts.PageSize, fn: fn}
+	if opts.PageSize == 0 && last.Links.Self != nil {
+		s.pageSize = last.Links.Self.Size
+	}
+	if number, ok := lastPageNumber(last); ok {
+		s.last = number
+		err = c.scanPages(ctx, opts, workers, s, last)
+	} else {
+		err = c.scanSequential(ctx, opts, s)
+	}
+	if err != nil {
+		return &s.report, err
+	}
+	return &s.report, c.relist(ctx, opts, workers, s, last)
+}
+
+// scanPages delivers the pages before last with concurrent requests and then last.
+func (c *Client) scanPages(ctx context.Context, opts ScanOptions, workers int, s *scanner, last *Page) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		number int
+		page   *Page
+		err    error
+	}
+	numbers := make(chan int)
+	results := make(chan result)
+	window := make(chan struct{}, 2*workers) // A token for every page fetched, but not delivered yet.
+	go func() {
+		defer close(numbers)
+		for n := 0; n < s.last; n++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case numbers <- n:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range numbers {
+				page, err := c.ListPageContext(ctx, ListOptions{PageNumber: n, PageSize: opts.PageSize, Filter: opts.Filter})
+				select {
+				case results <- result{number: n, page: page, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := map[int]*Page{} // Pages that arrived before the previous pages in page order.
+	next := 0
+	for r := range results {
+		if r.err != nil {
+			return r.err
+		}
+		if opts.Unordered {
+			if err := s.deliver(r.number, r.page); err != nil {
+				return err
+			}
+			<-window
+			continue
+		}
+		pending[r.number] = r.page
+		for page, ok := pending[next]; ok; page, ok = pending[next] {
+			delete(pending, next)
+			if err := s.deliver(next, page); err != nil {
+				return err
+			}
+			<-window
+			next++
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.deliver(s.last, last)
+}
+
+// relist fetches the last page again and, if it is different from last, fetched at the start of the scan,
+// or the scan found shifted accounts, lists all pages once more and delivers the accounts that were missed.
+func (c *Client) relist(ctx context.Context, opts ScanOptions, workers int, s *scanner, last *Page) error {
+	current, err := c.ListPageContext(ctx, ListOptions{LastPage: true, PageSize: opts.PageSize, Filter: opts.Filter})
+	if err != nil {
+		return err
+	}
+	number, ok := lastPageNumber(current)
+	if s.report.Consistent() && samePage(current, last) && (!ok || number == s.last) {
+		return nil
+	}
+	s.relisting = true
+	s.report.Relisted = true
+	if !ok {
+		return c.scanSequential(ctx, opts, s)
+	}
+	s.last = number
+	return c.scanPages(ctx, opts, workers, s, current)
+}
+
+// lastPageNumber returns the number of the last page from its links.
+func lastPageNumber(last *Page) (int, bool) {
+	if last.Links.Self != nil && last.Links.Self.Number >= 0 {
+		return last.Links.Self.Number, true
+	}
+	if last.Links.Last != nil && last.Links.Last.Number >= 0 {
+		return last.Links.Last.Number, true
+	}
+	return 0, false
+}
+
+// samePage returns true if both pages hold the same accounts in the same order.
+func samePage(a, b *Page) bool {
+	if len(a.Accounts) != len(b.Accounts) {
+		return false
+	}
+	for i := range a.Accounts {
+		if a.Accounts[i].ID != b.Accounts[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// scanSequential delivers the pages one after another, following the next links.
+func (c *Client) scanSequential(ctx context.Context, opts ScanOptions, s *scanner) error {
+	page, err := c.ListPageContext(ctx, ListOptions{PageSize: opts.PageSize, Filter: opts.Filter})
+	for number := 0; ; number++ {
+		if err != nil {
+			return err
+		}
+		s.last = number + 1
+		if !page.HasNext() || len(page.Accounts) == 0 {
+			s.last = number
+		}
+		if err := s.deliver(number, page); err != nil {
+			return err
+		}
+		if s.last == number {
+			return nil
+		}
+		var next string
+		if next, err = nextEndpoint(page); err == nil {
+			page, err = c.listEndpoint(ctx, next)
+		}
+	}
+}
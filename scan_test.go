@@ -0,0 +1,143 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"errors"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestScan verifies that all pages are delivered in page order, or all accounts unordered.
+func TestScan(t *testing.T) {
+	accounts := generateAccounts(95)
+	s := &pagedServer{accounts: accounts}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	scanned := []uuid.UUID{}
+	report, err := client.Scan(account.ScanOptions{PageSize: 10, Workers: 3}, func(acc *data.Account) error {
+		scanned = append(scanned, acc.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Scan should succeed: %v", err)
+	}
+	if len(scanned) != len(accounts) || report.Accounts != len(accounts) || report.Pages != 10 || !report.Consistent() {
+		t.Fatalf("Expected %d accounts on 10 pages, got %d, report %+v", len(accounts), len(scanned), report)
+	}
+	for i := range accounts {
+		if scanned[i] != accounts[i].ID {
+			t.Fatalf("Account #%d expected with id %s, got %s", i, accounts[i].ID, scanned[i])
+		}
+	}
+	// The last page is fetched once more at the end, nothing changed, so the pages aren't listed again.
+	if n := atomic.LoadInt32(&s.requests); n != 11 || report.Relisted {
+		t.Errorf("Expected 11 page requests, got %d, report %+v", n, report)
+	}
+
+	unordered := map[uuid.UUID]bool{}
+	report, err = client.Scan(account.ScanOptions{PageSize: 7, Workers: 5, Unordered: true}, func(acc *data.Account) error {
+		unordered[acc.ID] = true
+		return nil
+	})
+	if err != nil || len(unordered) != len(accounts) || report.Pages != 14 {
+		t.Errorf("Expected %d accounts on 14 pages, got %d, report %+v, error %v", len(accounts), len(unordered), report, err)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	_, err = client.Scan(account.ScanOptions{PageSize: 10}, func(acc *data.Account) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("Expected the scan to stop with the callback error after 1 call, got %v after %d calls", err, calls)
+	}
+}
+
+// TestScanShift verifies that accounts shifting between pages during the scan are reported and the missed accounts delivered.
+func TestScanShift(t *testing.T) {
+	accounts := generateAccounts(40)
+	inserted := generateAccounts(1)[0]
+	deleted := false
+	s := &pagedServer{accounts: accounts, onPage: func(s *pagedServer, number int) {
+		switch {
+		case number == 1 && len(s.accounts) == 40:
+			// An account is created before page 1 is served: the last account of page 0 moves to page 1.
+			s.accounts = append([]data.Account{inserted}, s.accounts...)
+		case number == 2 && !deleted:
+			// 15 accounts are deleted before page 2 is served: page 2 is short and accounts 19-29 move
+			// to pages 0 and 1 that were already fetched.
+			deleted = true
+			s.accounts = s.accounts[15:]
+		}
+	}}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	scanned := map[uuid.UUID]bool{}
+	report, err := client.Scan(account.ScanOptions{PageSize: 10, Workers: 1}, func(acc *data.Account) error {
+		scanned[acc.ID] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Scan should succeed: %v", err)
+	}
+	// The accounts of the last page, fetched at the start, moved to page 2 and are duplicates too.
+	if report.Consistent() || len(report.Duplicates) == 0 || report.Duplicates[0] != accounts[9].ID {
+		t.Errorf("Expected account %s reported as the first duplicate, got %+v", accounts[9].ID, report)
+	}
+	if len(report.ShortPages) != 1 || report.ShortPages[0] != 2 {
+		t.Errorf("Expected page 2 reported as short, got %+v", report)
+	}
+	if !report.Relisted || len(report.Missed) != 11 {
+		t.Fatalf("Expected accounts 19-29 reported as missed, got %+v", report)
+	}
+	for i, id := range report.Missed {
+		if id != accounts[19+i].ID {
+			t.Errorf("Missed account #%d expected with id %s, got %s", i, accounts[19+i].ID, id)
+		}
+	}
+	for i := range accounts {
+		if !scanned[accounts[i].ID] {
+			t.Errorf("Account #%d wasn't delivered", i)
+		}
+	}
+}
+
+// TestScanGrowth verifies that accounts, created after the last page was fetched, are found on the new pages.
+func TestScanGrowth(t *testing.T) {
+	accounts := generateAccounts(30)
+	created := generateAccounts(5)
+	s := &pagedServer{accounts: accounts, onPage: func(s *pagedServer, number int) {
+		if number == 1 && len(s.accounts) == 30 {
+			s.accounts = append(s.accounts, created...) // Page 3 is added after the scan has started.
+		}
+	}}
+	server := httptest.NewServer(s)
+	defer server.Close()
+	client := newTestClient(t, server, account.RetryPolicy{})
+
+	delivered := 0
+	report, err := client.Scan(account.ScanOptions{PageSize: 10, Workers: 2}, func(acc *data.Account) error {
+		delivered++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Scan should succeed: %v", err)
+	}
+	if delivered != 35 || report.Pages != 3 || !report.Relisted || len(report.Missed) != len(created) {
+		t.Fatalf("Expected 35 accounts with %d missed, got %d, report %+v", len(created), delivered, report)
+	}
+	for i := range created {
+		if report.Missed[i] != created[i].ID {
+			t.Errorf("Missed account #%d expected with id %s, got %s", i, created[i].ID, report.Missed[i])
+		}
+	}
+}
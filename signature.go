@@ -0,0 +1,178 @@
+package account
+
+import (
+	"accountapi/lib"
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SignatureAlgorithm is the only algorithm supported by Signer and Verifier.
+const SignatureAlgorithm = "rsa-sha256"
+
+// signedHeaders are the headers covered by the signature, (request-target) is the lowercase
+// method and the request URI.
+var signedHeaders = []string{"(request-target)", "host", "date", "digest"}
+
+// Signer signs requests with HTTP message signatures (draft-cavage-http-signatures), as required
+// by the Form3 API. Every request gets a Date header, a Digest header with the SHA-256 of the body
+// and an Authorization header with the signature of (request-target), Host, Date and Digest.
+// When the request already has an Authorization header (e.g. a bearer token), the signature is
+// sent in the Signature header instead.
+type Signer struct {
+	KeyID string          // Key ID, registered with the API.
+	Key   *rsa.PrivateKey // Private key that signs requests.
+}
+
+// Sign adds Date, Digest and Authorization headers to req, body is the request body.
+func (s *Signer) Sign(req *http.Request, body []byte) error {
+	if s.Key == nil || s.KeyID == "" {
+		return lib.NewErrorInvalidArgument("signer requires KeyID and Key")
+	}
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Digest", digest(body))
+	hashed := sha256.Sum256([]byte(signingString(req, signedHeaders)))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.Key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+	params := fmt.Sprintf(`keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		s.KeyID, SignatureAlgorithm, strings.Join(signedHeaders, " "), base64.StdEncoding.EncodeToString(signature))
+	if req.Header.Get("Authorization") != "" {
+		req.Header.Set("Signature", params)
+	} else {
+		req.Header.Set("Authorization", "Signature "+params)
+	}
+	return nil
+}
+
+// Verifier verifies HTTP message signatures of requests, signed by Signer.
+type Verifier struct {
+	Keys    map[string]*rsa.PublicKey // Public keys by key ID.
+	MaxSkew time.Duration             // Maximum difference between the Date header and the current time, 0 disables the check.
+}
+
+// Verify verifies the signature and the body digest of r. The body is read and replaced,
+// so it can still be read by the caller. It returns ErrorSignature if the signature is invalid.
+func (v *Verifier) Verify(r *http.Request) error {
+	signatureHeader := r.Header.Get("Signature")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Signature ") {
+		signatureHeader = strings.TrimPrefix(auth, "Signature ")
+	}
+	if signatureHeader == "" {
+		return lib.NewErrorSignature("missing Authorization: Signature or Signature header")
+	}
+	params := parseSignatureParams(signatureHeader)
+	key, ok := v.Keys[params["keyId"]]
+	if !ok {
+		return lib.NewErrorSignature("unknown keyId " + params["keyId"])
+	}
+	if params["algorithm"] != SignatureAlgorithm {
+		return lib.NewErrorSignature("unsupported algorithm " + params["algorithm"])
+	}
+	headers := strings.Fields(params["headers"])
+	for _, required := range signedHeaders {
+		if !contains(headers, required) {
+			return lib.NewErrorSignature("header " + required + " is not signed")
+		}
+	}
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return lib.NewErrorSignature("signature is not base64 encoded")
+	}
+	hashed := sha256.Sum256([]byte(signingString(r, headers)))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature); err != nil {
+		return lib.NewErrorSignature("signature does not match")
+	}
+	if v.MaxSkew > 0 {
+		date, err := http.ParseTime(r.Header.Get("Date"))
+		if err != nil {
+			return lib.NewErrorSignature("invalid Date header")
+		}
+		if skew := time.Since(date); skew > v.MaxSkew || skew < -v.MaxSkew {
+			return lib.NewErrorSignature("Date header is out of range")
+		}
+	}
+	var body []byte
+	if r.Body != nil {
+		body, err = ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	if r.Header.Get("Digest") != digest(body) {
+		return lib.NewErrorSignature("digest does not match the body")
+	}
+	return nil
+}
+
+// Handler returns a handler that passes requests with a valid signature to next and
+// rejects others with 401 Unauthorized.
+func (v *Verifier) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := v.Verify(r); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{"error_message":%q}`, err.Error())
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// digest returns the value of the Digest header for body.
+func digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// signingString builds the string that is signed from the headers of r.
+func signingString(r *http.Request, headers []string) string {
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("%s: %s %s", h, strings.ToLower(r.Method), r.URL.RequestURI()))
+		case "host":
+			host := r.Host
+			if host == "" {
+				host = r.URL.Host
+			}
+			lines = append(lines, h+": "+host)
+		default:
+			lines = append(lines, h+": "+r.Header.Get(h))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseSignatureParams parses the comma separated key="value" parameters of a signature.
+func parseSignatureParams(v string) map[string]string {
+	params := map[string]string{}
+	for _, p := range strings.Split(v, ",") {
+		kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+		if len(kv) == 2 {
+			params[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return params
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/lib"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const TestKeyID = "test-key"
+
+// TestSignature verifies that signed requests pass the Verifier and unsigned or modified requests don't.
+func TestSignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := &account.Verifier{
+		Keys:    map[string]*rsa.PublicKey{TestKeyID: &key.PublicKey},
+		MaxSkew: time.Minute,
+	}
+	acc := generateBasicAccount()
+	server := httptest.NewServer(verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST":
+			writeAccount(w, http.StatusCreated, acc)
+		default:
+			writeAccount(w, http.StatusOK, acc)
+		}
+	})))
+	defer server.Close()
+
+	client, err := account.New(account.Config{
+		Server:  server.URL,
+		Timeout: TestTimeout,
+		Signer:  &account.Signer{KeyID: TestKeyID, Key: key},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Create(acc); err != nil {
+		t.Errorf("Signed create should be accepted: %v", err)
+	}
+	if _, err := client.Fetch(acc.ID); err != nil {
+		t.Errorf("Signed fetch should be accepted: %v", err)
+	}
+	if err := client.Delete(acc.ID, acc.Version); err != nil {
+		t.Errorf("Signed delete should be accepted: %v", err)
+	}
+
+	_, err = newTestClient(t, server, account.RetryPolicy{}).Fetch(acc.ID)
+	if !lib.IsErrorAPI(err) || err.(*lib.ErrorAPI).StatusCode != http.StatusUnauthorized {
+		t.Errorf("Unsigned request should be rejected with 401, got: %v", err)
+	}
+
+	// A signed request with a modified body fails the digest check.
+	body := `{"data":{}}`
+	req := httptest.NewRequest("POST", "/v1/organisation/accounts", strings.NewReader(body))
+	signer := &account.Signer{KeyID: TestKeyID, Key: key}
+	if err := signer.Sign(req, []byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(req); err != nil {
+		t.Errorf("Signed request should be verified: %v", err)
+	}
+	req = httptest.NewRequest("POST", "/v1/organisation/accounts", strings.NewReader(`{"data":{"id":"x"}}`))
+	if err := signer.Sign(req, []byte(body)); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.Verify(req); !lib.IsErrorSignature(err) {
+		t.Errorf("Modified body should fail verification, got: %v", err)
+	}
+	// A signed request with a modified target fails the signature check.
+	req = httptest.NewRequest("GET", "/v1/organisation/accounts/1", nil)
+	if err := signer.Sign(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	req.URL.Path = "/v1/organisation/accounts/2"
+	if err := verifier.Verify(req); !lib.IsErrorSignature(err) {
+		t.Errorf("Modified request target should fail verification, got: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package account
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Subscriptions manages the subscriptions to the notifications about changed records.
+type Subscriptions struct {
+	c *Client
+}
+
+// Subscriptions returns the client of the notification subscriptions.
+func (c *Client) Subscriptions() *Subscriptions {
+	return &Subscriptions{c: c}
+}
+
+// SubscriptionListOptions selects the page of the subscription List request and the record type.
+type SubscriptionListOptions struct {
+	PageOptions
+	RecordType data.RecordType // Only the subscriptions of the record type are listed, all if RTNone.
+}
+
+// SubscriptionPage is a page of subscriptions with links to the neighbouring pages.
+type SubscriptionPage struct {
+	Subscriptions []data.Subscription
+	Pagination
+}
+
+// Create creates a subscription, e.g. for the created accounts or for the account events, the callback
+// transport is "http" if not set. On success, it returns the subscription data, returned by the server,
+// otherwise it returns ErrorInvalidArgument for a record type other than Accounts or AccountEvents or
+// an invalid event type, or an ErrorAPI error that includes the error message, returned by the server.
+// Create is not retried, a subscription that was created by a failed attempt would be a duplicate.
+func (s *Subscriptions) Create(subscription *data.Subscription) (*data.Subscription, error) {
+	return s.CreateContext(context.Background(), subscription)
+}
+
+// CreateContext is Create with a context that can cancel the request or set its deadline.
+func (s *Subscriptions) CreateContext(ctx context.Context, subscription *data.Subscription) (*data.Subscription, error) {
+	attributes := subscription.Attributes
+	if attributes.RecordType != data.Accounts && attributes.RecordType != data.AccountEvents {
+		return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("recordType=%d", attributes.RecordType))
+	}
+	if attributes.EventType != nil && !attributes.EventType.IsValid() {
+		return nil, lib.NewErrorInvalidArgument(fmt.Sprintf("eventType=%d", *attributes.EventType))
+	}
+	requestType := data.Subscriptions
+	if attributes.CallbackTransport == "" {
+		attributes.CallbackTransport = "http"
+	}
+	jResult := data.ResponseSubscription{}
+	bin, err := json.Marshal(&data.RequestCreateSubscription{
+		Data: data.SubscriptionData{
+			ID:             subscription.ID,
+			OrganisationID: subscription.OrganisationID,
+			Type:           &requestType,
+			Attributes:     attributes,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = s.c.post(ctx, &Operation{
+		Name:     "CreateSubscription",
+		Endpoint: "/v1/notification/subscriptions",
+		Body:     bin,
+		Response: &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return subscriptionFromData(&jResult.Data), nil
+}
+
+// List retrieves a page of subscriptions, selected by opts. On success, Subscriptions of the page is
+// an array of subscriptions (can be empty, but not nil), otherwise it returns ErrorInvalidArgument for
+// invalid options or an ErrorAPI error that includes the error message, returned by the server.
+func (s *Subscriptions) List(opts SubscriptionListOptions) (*SubscriptionPage, error) {
+	return s.ListContext(context.Background(), opts)
+}
+
+// ListContext is List with a context that can cancel the request or set its deadline.
+func (s *Subscriptions) ListContext(ctx context.Context, opts SubscriptionListOptions) (*SubscriptionPage, error) {
+	query, err := opts.query()
+	if err != nil {
+		return nil, err
+	}
+	if opts.RecordType != data.RTNone {
+		query.Set("filter[record_type]", opts.RecordType.String())
+	}
+	endpoint := "/v1/notification/subscriptions"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	jResult := data.ResponseSubscriptionList{}
+	err = s.c.get(ctx, &Operation{
+		Name:     "ListSubscriptions",
+		Endpoint: endpoint,
+		Response: &jResult,
+	})
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := []data.Subscription{}
+	for i := range jResult.Data {
+		subscriptions = append(subscriptions, *subscriptionFromData(&jResult.Data[i]))
+	}
+	return &SubscriptionPage{Subscriptions: subscriptions, Pagination: pageLinks(jResult.Links)}, nil
+}
+
+// Delete deletes the subscription identified by id and version. On success it returns nil, otherwise
+// it returns an ErrorAPI error that includes the error message, returned by the server.
+func (s *Subscriptions) Delete(id uuid.UUID, version int) error {
+	return s.DeleteContext(context.Background(), id, version)
+}
+
+// DeleteContext is Delete with a context that can cancel the request or set its deadline.
+func (s *Subscriptions) DeleteContext(ctx context.Context, id uuid.UUID, version int) error {
+	return s.c.delete(ctx, &Operation{
+		Name:     "DeleteSubscription",
+		Endpoint: fmt.Sprintf("/v1/notification/subscriptions/%s?version=%d", id.String(), version),
+	})
+}
+
+// subscriptionFromData copies the values from subscription data into Subscription structure.
+func subscriptionFromData(d *data.SubscriptionData) *data.Subscription {
+	subscription := &data.Subscription{
+		ID:             d.ID,
+		OrganisationID: d.OrganisationID,
+		Attributes:     d.Attributes,
+	}
+	if d.Version != nil {
+		subscription.Version = *d.Version
+	}
+	return subscription
+}
@@ -0,0 +1,131 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestSubscriptions verifies creating, listing and deleting subscriptions.
+func TestSubscriptions(t *testing.T) {
+	var created data.SubscriptionData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/v1/notification/subscriptions":
+			var req data.RequestCreateSubscription
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Data.Type == nil || *req.Data.Type != data.Subscriptions {
+				writeError(w, http.StatusBadRequest, "invalid request")
+				return
+			}
+			version := 0
+			created = req.Data
+			created.Version = &version
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&data.ResponseSubscription{Data: created})
+		case r.Method == "GET" && r.URL.Path == "/v1/notification/subscriptions":
+			list := []data.SubscriptionData{}
+			if r.URL.Query().Get("filter[record_type]") == "accounts" {
+				list = append(list, created)
+			}
+			_ = json.NewEncoder(w).Encode(&data.ResponseSubscriptionList{Data: list})
+		case r.Method == "DELETE" && r.URL.Path == "/v1/notification/subscriptions/"+created.ID.String() && r.URL.Query().Get("version") == "0":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusNotFound, "not found")
+		}
+	}))
+	defer server.Close()
+	subscriptions := newTestClient(t, server, account.RetryPolicy{}).Subscriptions()
+
+	eventType := data.EventCreated
+	subscription, err := subscriptions.Create(&data.Subscription{
+		ID:             uuid.New(),
+		OrganisationID: uuid.New(),
+		Attributes: data.SubscriptionAttributes{
+			CallbackURI: "https://example.com/notifications",
+			RecordType:  data.Accounts,
+			EventType:   &eventType,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Create should succeed: %v", err)
+	}
+	if subscription.Attributes.CallbackTransport != "http" || subscription.Attributes.RecordType != data.Accounts ||
+		subscription.Attributes.EventType == nil || *subscription.Attributes.EventType != data.EventCreated {
+		t.Errorf("Expected an http subscription for created accounts, got %+v", subscription.Attributes)
+	}
+	page, err := subscriptions.List(account.SubscriptionListOptions{RecordType: data.Accounts})
+	if err != nil || len(page.Subscriptions) != 1 || page.Subscriptions[0].ID != subscription.ID {
+		t.Errorf("List should return the subscription, got %+v, %v", page, err)
+	}
+	if err := subscriptions.Delete(subscription.ID, subscription.Version); err != nil {
+		t.Errorf("Delete should succeed: %v", err)
+	}
+}
+
+// TestSubscriptionCreateNotRetried verifies that a subscription create, which could be a duplicate, is not retried.
+func TestSubscriptionCreateNotRetried(t *testing.T) {
+	var posts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&posts, 1) == 1 {
+			writeError(w, http.StatusServiceUnavailable, "unavailable")
+			return
+		}
+		writeError(w, http.StatusConflict, "duplicate")
+	}))
+	defer server.Close()
+	subscriptions := newTestClient(t, server, testRetryPolicy).Subscriptions()
+
+	_, err := subscriptions.Create(&data.Subscription{
+		ID:             uuid.New(),
+		OrganisationID: uuid.New(),
+		Attributes:     data.SubscriptionAttributes{RecordType: data.Accounts},
+	})
+	if !isStatus(err, http.StatusServiceUnavailable) || atomic.LoadInt32(&posts) != 1 {
+		t.Errorf("Expected the 503 of a single attempt, got %v after %d attempts", err, posts)
+	}
+}
+
+// TestSubscriptionCreateInvalid verifies that subscriptions with an unsupported record type or event type
+// are rejected before they are sent, and that a nil event type is omitted.
+func TestSubscriptionCreateInvalid(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		writeError(w, http.StatusBadRequest, "invalid")
+	}))
+	defer server.Close()
+	subscriptions := newTestClient(t, server, account.RetryPolicy{}).Subscriptions()
+
+	invalidEventType := data.AccountEventType(100)
+	for _, attributes := range []data.SubscriptionAttributes{
+		{},
+		{RecordType: data.Organisations},
+		{RecordType: data.RecordType(100)},
+		{RecordType: data.Accounts, EventType: &invalidEventType},
+	} {
+		_, err := subscriptions.Create(&data.Subscription{ID: uuid.New(), OrganisationID: uuid.New(), Attributes: attributes})
+		if !lib.IsErrorInvalidArgument(err) {
+			t.Errorf("Expected ErrorInvalidArgument for %+v, got %v", attributes, err)
+		}
+	}
+	if len(bodies) != 0 {
+		t.Fatalf("Expected no requests, got %d", len(bodies))
+	}
+
+	_, _ = subscriptions.Create(&data.Subscription{ID: uuid.New(), OrganisationID: uuid.New(), Attributes: data.SubscriptionAttributes{RecordType: data.AccountEvents}})
+	if len(bodies) != 1 || strings.Contains(bodies[0], "event_type") {
+		t.Errorf("Expected a request without event_type, got %q", bodies)
+	}
+}
@@ -0,0 +1,94 @@
+// Package tracing instruments the account client with OpenTelemetry spans.
+package tracing
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the tracer of the account client.
+const InstrumentationName = "accountapi"
+
+// Attribute keys of account operation spans.
+const (
+	OperationKey      = attribute.Key("account.operation")
+	AccountIDKey      = attribute.Key("account.id")
+	OrganisationIDKey = attribute.Key("account.organisation_id")
+	PageNumberKey     = attribute.Key("account.page.number")
+	PageSizeKey       = attribute.Key("account.page.size")
+	RetryCountKey     = attribute.Key("account.retry_count")
+	MethodKey         = attribute.Key("http.request.method")
+	StatusCodeKey     = attribute.Key("http.response.status_code")
+)
+
+// Middleware returns an account.Middleware that creates a client span for every operation and injects
+// W3C trace-context headers into its requests. Spans are created by tracers from tp, or from the global
+// tracer provider if tp is nil.
+func Middleware(tp trace.TracerProvider) account.Middleware {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	tracer := tp.Tracer(InstrumentationName)
+	propagator := propagation.TraceContext{}
+	return func(next account.Handler) account.Handler {
+		return func(ctx context.Context, op *account.Operation) error {
+			ctx, span := tracer.Start(ctx, "account."+op.Name, trace.WithSpanKind(trace.SpanKindClient))
+			defer span.End()
+			span.SetAttributes(OperationKey.String(op.Name), MethodKey.String(op.Method))
+			if op.AccountID != uuid.Nil {
+				span.SetAttributes(AccountIDKey.String(op.AccountID.String()))
+			}
+			span.SetAttributes(pageAttributes(op.Endpoint)...)
+			if op.Header == nil {
+				op.Header = http.Header{}
+			}
+			propagator.Inject(ctx, propagation.HeaderCarrier(op.Header))
+
+			err := next(ctx, op)
+			if op.StatusCode != 0 {
+				span.SetAttributes(StatusCodeKey.Int(op.StatusCode))
+			}
+			if op.Attempts > 1 {
+				span.SetAttributes(RetryCountKey.Int(op.Attempts - 1))
+			}
+			if r, ok := op.Response.(*data.ResponseData); ok && err == nil && r.Data.OrganisationID != uuid.Nil {
+				span.SetAttributes(OrganisationIDKey.String(r.Data.OrganisationID.String()))
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+}
+
+// pageAttributes returns the page number and size attributes from the query of a List endpoint.
+func pageAttributes(endpoint string) []attribute.KeyValue {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil
+	}
+	attrs := []attribute.KeyValue{}
+	query := u.Query()
+	if v := query.Get("page[number]"); v != "" {
+		attrs = append(attrs, PageNumberKey.String(v))
+	}
+	if v := query.Get("page[size]"); v != "" {
+		if size, err := strconv.Atoi(v); err == nil {
+			attrs = append(attrs, PageSizeKey.Int(size))
+		}
+	}
+	return attrs
+}
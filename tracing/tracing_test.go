@@ -0,0 +1,124 @@
+package tracing_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"accountapi/lib"
+	"accountapi/tracing"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biter777/countries"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracing verifies spans, their attributes and trace-context propagation with an in-memory exporter.
+func TestTracing(t *testing.T) {
+	id := uuid.New()
+	org := uuid.New()
+	version := 0
+	recordType := data.Accounts
+	var attempts int32
+	traceparents := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(data.ErrorMessage{Message: "not found"})
+		case r.URL.Path == "/v1/organisation/accounts/":
+			_ = json.NewEncoder(w).Encode(&data.ResponseDataList{})
+		case atomic.AddInt32(&attempts, 1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(w).Encode(data.ErrorMessage{Message: "unavailable"})
+		default:
+			_ = json.NewEncoder(w).Encode(&data.ResponseData{Data: data.AccountData{
+				ID:             id,
+				OrganisationID: org,
+				Type:           &recordType,
+				Version:        &version,
+				Attributes:     data.Attributes{Country: data.NewCountryCode(countries.UnitedKingdom)},
+			}})
+		}
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	client, err := account.New(account.Config{
+		Server:     server.URL,
+		Timeout:    3,
+		Retry:      account.RetryPolicy{MaxAttempts: 2, BaseBackoff: time.Millisecond},
+		Middleware: []account.Middleware{tracing.Middleware(tp)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := client.FetchContext(ctx, id); err != nil {
+		t.Fatalf("Fetch should succeed: %v", err)
+	}
+	if _, err := client.ListContext(ctx, lib.PageNumber(2), lib.PageSize(10)); err != nil {
+		t.Fatalf("List should succeed: %v", err)
+	}
+	if err := client.DeleteContext(ctx, id, 0); !lib.IsErrorAPI(err) {
+		t.Fatalf("Delete should fail with ErrorAPI: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	fetch, list, del := spans[0], spans[1], spans[2]
+	expectAttributes(t, fetch, map[attribute.Key]attribute.Value{
+		tracing.OperationKey:      attribute.StringValue("Fetch"),
+		tracing.AccountIDKey:      attribute.StringValue(id.String()),
+		tracing.OrganisationIDKey: attribute.StringValue(org.String()),
+		tracing.StatusCodeKey:     attribute.IntValue(http.StatusOK),
+		tracing.RetryCountKey:     attribute.IntValue(1),
+	})
+	expectAttributes(t, list, map[attribute.Key]attribute.Value{
+		tracing.OperationKey:  attribute.StringValue("List"),
+		tracing.PageNumberKey: attribute.StringValue("2"),
+		tracing.PageSizeKey:   attribute.IntValue(10),
+	})
+	expectAttributes(t, del, map[attribute.Key]attribute.Value{
+		tracing.OperationKey:  attribute.StringValue("Delete"),
+		tracing.StatusCodeKey: attribute.IntValue(http.StatusNotFound),
+	})
+	if del.Status.Code != codes.Error {
+		t.Errorf("Expected an error status of the failed Delete span, got %v", del.Status)
+	}
+
+	// Both Fetch attempts carry the trace context of the Fetch span.
+	for _, span := range []tracetest.SpanStub{fetch, fetch, list, del} {
+		traceparent := <-traceparents
+		expected := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+		if traceparent != expected {
+			t.Errorf("Expected traceparent %s, got %s", expected, traceparent)
+		}
+	}
+}
+
+func expectAttributes(t *testing.T, span tracetest.SpanStub, expected map[attribute.Key]attribute.Value) {
+	t.Helper()
+	found := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		found[kv.Key] = kv.Value
+	}
+	for k, v := range expected {
+		if found[k] != v {
+			t.Errorf("%s: expected attribute %s=%s, got %s", span.Name, k, v.Emit(), found[k].Emit())
+		}
+	}
+}
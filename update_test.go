@@ -0,0 +1,69 @@
+package account_test
+
+import (
+	"accountapi/data"
+	"accountapi/lib"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestUpdate verifies that an update sends the version, returns the new version and fails with
+// ErrorConflict when the version doesn't match.
+func TestUpdate(t *testing.T) {
+	stored := generateBasicAccount()
+	stored.Version = 1
+	var mu sync.Mutex
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Method != "PATCH" || r.URL.Path != "/v1/organisation/accounts/"+stored.ID.String() {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		var req data.RequestUpdate
+		if err := json.Unmarshal(body, &req); err != nil || req.Data.Version == nil {
+			writeError(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if *req.Data.Version != stored.Version {
+			writeError(w, http.StatusConflict, "version mismatch")
+			return
+		}
+		stored.Version++
+		stored.Attributes = req.Data.Attributes
+		writeAccount(w, http.StatusOK, stored)
+	}))
+	defer server.Close()
+	client := newTestClient(t, server, testRetryPolicy)
+
+	acc := *stored
+	acc.Attributes.BankID = "400302"
+	updated, err := client.Update(&acc)
+	if err != nil {
+		t.Fatalf("Update should succeed: %v", err)
+	}
+	if updated.Version != 2 || updated.Attributes.BankID != "400302" {
+		t.Errorf("Expected version 2 with the new bank ID, got version %d, bank ID %s", updated.Version, updated.Attributes.BankID)
+	}
+
+	// acc still has version 1.
+	atomic.StoreInt32(&requests, 0)
+	_, err = client.Update(&acc)
+	if !lib.IsErrorConflict(err) {
+		t.Fatalf("Expected ErrorConflict, got %v", err)
+	}
+	if e := lib.ErrorCauser(err).(*lib.ErrorConflict); e.Version != 1 || e.ErrorMessage != "version mismatch" {
+		t.Errorf("Expected conflict of version 1 with the server message, got %v", e)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("Expected a single request, conflicts are not retried, got %d", n)
+	}
+}
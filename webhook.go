@@ -0,0 +1,209 @@
+package account
+
+import (
+	"accountapi/data"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWebhookMaxBody is the size limit of a notification body when Webhook.MaxBody is not set.
+	DefaultWebhookMaxBody = 1 << 20
+	// DefaultWebhookMaxSkew is the maximum age of a notification's Date header when Verifier.MaxSkew is not set.
+	DefaultWebhookMaxSkew = 5 * time.Minute
+)
+
+// AccountNotificationHandler handles a notification about an account, its error makes the sender repeat the notification.
+type AccountNotificationHandler func(ctx context.Context, n *data.Notification, account *data.Account) error
+
+// AccountEventNotificationHandler handles a notification about an account event, its error makes the sender repeat the notification.
+type AccountEventNotificationHandler func(ctx context.Context, n *data.Notification, event *data.AccountEvent) error
+
+// Webhook is an http.Handler that receives the notification callbacks of subscriptions, decodes the
+// changed accounts and account events and dispatches them to the registered handlers. It responds
+// with 204 No Content when all the handlers succeeded, 401 Unauthorized if the signature is not valid,
+// 413 Request Entity Too Large if the body is larger than MaxBody, 400 Bad Request if the notification
+// can't be decoded and 500 Internal Server Error if a handler failed, so the notification is sent again.
+// Handler errors are not sent back, they are passed to OnError.
+// Without a Verifier all callbacks are rejected with 500 Internal Server Error, unless Insecure is set.
+type Webhook struct {
+	Verifier *Verifier                        // Verifies the signatures and the Date headers of the callbacks, DefaultWebhookMaxSkew if its MaxSkew is not set.
+	Insecure bool                             // Accepts callbacks without verifying them when Verifier is nil, e.g. in tests.
+	MaxBody  int64                            // Size limit of a notification body in bytes, DefaultWebhookMaxBody if not set.
+	OnError  func(r *http.Request, err error) // Called with the error of a failed handler or a missing Verifier, e.g. to log it.
+
+	mu            sync.RWMutex
+	accounts      []accountNotificationHandler
+	accountEvents []AccountEventNotificationHandler
+}
+
+// accountNotificationHandler is a handler for the notifications about accounts of the event types.
+type accountNotificationHandler struct {
+	eventTypes []data.AccountEventType // All event types if empty.
+	handle     AccountNotificationHandler
+}
+
+// HandleAccount registers a handler for the notifications about accounts with one of eventTypes,
+// e.g. data.EventCreated, or for all notifications about accounts if no event type is given.
+func (h *Webhook) HandleAccount(handle AccountNotificationHandler, eventTypes ...data.AccountEventType) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.accounts = append(h.accounts, accountNotificationHandler{eventTypes: eventTypes, handle: handle})
+}
+
+// HandleAccountEvent registers a handler for the notifications about account events, e.g. status changes.
+func (h *Webhook) HandleAccountEvent(handle AccountEventNotificationHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.accountEvents = append(h.accountEvents, handle)
+}
+
+// ServeHTTP receives a notification callback.
+func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.Verifier == nil && !h.Insecure {
+		if h.OnError != nil {
+			h.OnError(r, errors.New("webhook has no Verifier and is not Insecure"))
+		}
+		writeWebhookError(w, http.StatusInternalServerError, errors.New("notification was not handled"))
+		return
+	}
+	if r.Method != "POST" {
+		writeWebhookError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	maxBody := h.MaxBody
+	if maxBody <= 0 {
+		maxBody = DefaultWebhookMaxBody
+	}
+	if r.ContentLength > maxBody {
+		writeWebhookError(w, http.StatusRequestEntityTooLarge, errors.New("notification is too large"))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+	if err := h.verify(r); err != nil {
+		writeWebhookError(w, readErrorStatus(err, http.StatusUnauthorized), err)
+		return
+	}
+	n := data.Notification{}
+	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+		writeWebhookError(w, readErrorStatus(err, http.StatusBadRequest), err)
+		return
+	}
+	var err error
+	switch n.RecordType {
+	case data.Accounts:
+		err = h.dispatchAccount(r.Context(), &n)
+	case data.AccountEvents:
+		err = h.dispatchAccountEvent(r.Context(), &n)
+	}
+	if isDecodeError(err) {
+		writeWebhookError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		if h.OnError != nil {
+			h.OnError(r, err)
+		}
+		writeWebhookError(w, http.StatusInternalServerError, errors.New("notification was not handled"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify verifies the signature of r with the Verifier, the Date header is checked with
+// DefaultWebhookMaxSkew if the Verifier doesn't set MaxSkew. Nothing is verified without a Verifier.
+func (h *Webhook) verify(r *http.Request) error {
+	if h.Verifier == nil {
+		return nil
+	}
+	verifier := *h.Verifier
+	if verifier.MaxSkew <= 0 {
+		verifier.MaxSkew = DefaultWebhookMaxSkew
+	}
+	return verifier.Verify(r)
+}
+
+// decodeError is the error of the notification data that can't be decoded.
+type decodeError struct {
+	err error
+}
+
+// Error ...
+func (e *decodeError) Error() string {
+	return "invalid notification data:" + e.err.Error()
+}
+
+// readErrorStatus returns 413 if err is caused by a body over the size limit, otherwise status.
+func readErrorStatus(err error, status int) int {
+	var errTooLarge *http.MaxBytesError
+	if errors.As(err, &errTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return status
+}
+
+// isDecodeError returns true if err is a decodeError.
+func isDecodeError(err error) bool {
+	_, ok := err.(*decodeError)
+	return ok
+}
+
+// dispatchAccount decodes the account of the notification and passes it to the matching handlers.
+func (h *Webhook) dispatchAccount(ctx context.Context, n *data.Notification) error {
+	d := data.AccountData{}
+	if err := json.Unmarshal(n.Data, &d); err != nil {
+		return &decodeError{err: err}
+	}
+	h.mu.RLock()
+	handlers := h.accounts
+	h.mu.RUnlock()
+	for _, handler := range handlers {
+		if len(handler.eventTypes) > 0 && !containsEventType(handler.eventTypes, n.EventType) {
+			continue
+		}
+		account := accountFromData(&d)
+		if err := handler.handle(ctx, n, &account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dispatchAccountEvent decodes the account event of the notification and passes it to the handlers.
+func (h *Webhook) dispatchAccountEvent(ctx context.Context, n *data.Notification) error {
+	d := data.AccountEventData{}
+	if err := json.Unmarshal(n.Data, &d); err != nil {
+		return &decodeError{err: err}
+	}
+	h.mu.RLock()
+	handlers := h.accountEvents
+	h.mu.RUnlock()
+	for _, handle := range handlers {
+		if err := handle(ctx, n, eventFromData(&d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containsEventType returns true if eventType is in list.
+func containsEventType(list []data.AccountEventType, eventType data.AccountEventType) bool {
+	for _, t := range list {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeWebhookError responds with status and the error message.
+func writeWebhookError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data.ErrorMessage{Message: err.Error()})
+}
@@ -0,0 +1,198 @@
+package account_test
+
+import (
+	account "accountapi"
+	"accountapi/data"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestWebhook verifies signed notifications end-to-end: verification, decoding and dispatching.
+func TestWebhook(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlerErrors := make(chan error, 1)
+	webhook := &account.Webhook{
+		Verifier: &account.Verifier{
+			Keys:    map[string]*rsa.PublicKey{TestKeyID: &key.PublicKey},
+			MaxSkew: time.Minute,
+		},
+		MaxBody: 4096,
+		OnError: func(r *http.Request, err error) { handlerErrors <- err },
+	}
+	created := make(chan *data.Account, 1)
+	events := make(chan *data.AccountEvent, 1)
+	webhook.HandleAccount(func(ctx context.Context, n *data.Notification, acc *data.Account) error {
+		created <- acc
+		return nil
+	}, data.EventCreated)
+	webhook.HandleAccount(func(ctx context.Context, n *data.Notification, acc *data.Account) error {
+		return errors.New("updates are not handled")
+	}, data.EventUpdated)
+	webhook.HandleAccountEvent(func(ctx context.Context, n *data.Notification, event *data.AccountEvent) error {
+		events <- event
+		return nil
+	})
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+	signer := &account.Signer{KeyID: TestKeyID, Key: key}
+
+	var message data.ErrorMessage
+	sendBody := func(body io.Reader, signedBody []byte) int {
+		req, _ := http.NewRequest("POST", server.URL+"/notifications", body)
+		if signedBody != nil {
+			if err := signer.Sign(req, signedBody); err != nil {
+				t.Fatal(err)
+			}
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		message = data.ErrorMessage{}
+		_ = json.NewDecoder(resp.Body).Decode(&message)
+		return resp.StatusCode
+	}
+	send := func(n *data.Notification, sign bool) int {
+		body, _ := json.Marshal(n)
+		if !sign {
+			return sendBody(bytes.NewReader(body), nil)
+		}
+		return sendBody(bytes.NewReader(body), body)
+	}
+
+	acc := generateBasicAccount()
+	recordType := data.Accounts
+	accountData, _ := json.Marshal(&data.AccountData{
+		ID:             acc.ID,
+		OrganisationID: acc.OrganisationID,
+		Type:           &recordType,
+		Version:        &acc.Version,
+		Attributes:     acc.Attributes,
+	})
+	n := &data.Notification{ID: uuid.New(), EventType: data.EventCreated, RecordType: data.Accounts, Data: accountData}
+	if status := send(n, true); status != http.StatusNoContent {
+		t.Fatalf("Expected 204 for a created account, got %d", status)
+	}
+	if got := <-created; got.ID != acc.ID || got.Attributes.BankID != acc.Attributes.BankID {
+		t.Errorf("Expected account %s, got %+v", acc.ID, got)
+	}
+	if status := send(n, false); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unsigned notification, got %d", status)
+	}
+	n.EventType = data.EventUpdated
+	if status := send(n, true); status != http.StatusInternalServerError || strings.Contains(message.Message, "updates") {
+		t.Errorf("Expected 500 without the handler error for a failed handler, got %d: %s", status, message.Message)
+	}
+	if err := <-handlerErrors; err.Error() != "updates are not handled" {
+		t.Errorf("Expected the handler error passed to OnError, got %v", err)
+	}
+
+	// Bodies over MaxBody are rejected, whether their length is known or not.
+	large, _ := json.Marshal(&data.Notification{ID: uuid.New(), RecordType: data.Accounts, Data: json.RawMessage(`"` + strings.Repeat("x", 5000) + `"`)})
+	if status := sendBody(bytes.NewReader(large), large); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for a large notification, got %d", status)
+	}
+	if status := sendBody(io.MultiReader(bytes.NewReader(large)), large); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for a large chunked notification, got %d", status)
+	}
+	n.Data = json.RawMessage(`{"id":"not-a-uuid"}`)
+	if status := send(n, true); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid data, got %d", status)
+	}
+
+	eventType := data.AccountEvents
+	eventData, _ := json.Marshal(&data.AccountEventData{
+		ID:         uuid.New(),
+		Type:       &eventType,
+		Attributes: data.AccountEventAttributes{AccountID: acc.ID, EventType: data.EventFailed, Status: data.Failed},
+	})
+	n = &data.Notification{ID: uuid.New(), EventType: data.EventCreated, RecordType: data.AccountEvents, Data: eventData}
+	if status := send(n, true); status != http.StatusNoContent {
+		t.Fatalf("Expected 204 for an account event, got %d", status)
+	}
+	if got := <-events; got.Attributes.AccountID != acc.ID || got.Attributes.Status != data.Failed {
+		t.Errorf("Expected the failure of account %s, got %+v", acc.ID, got)
+	}
+}
+
+// TestWebhookNotVerified verifies that callbacks are rejected without a Verifier unless the webhook is Insecure.
+func TestWebhookNotVerified(t *testing.T) {
+	body := []byte(`{"id":"` + uuid.New().String() + `","record_type":"accounts","data":{}}`)
+	var handlerErr error
+	webhook := &account.Webhook{OnError: func(r *http.Request, err error) { handlerErr = err }}
+	recorder := httptest.NewRecorder()
+	webhook.ServeHTTP(recorder, httptest.NewRequest("POST", "/notifications", bytes.NewReader(body)))
+	if recorder.Code != http.StatusInternalServerError || handlerErr == nil {
+		t.Errorf("Expected 500 and an error passed to OnError without a Verifier, got %d, %v", recorder.Code, handlerErr)
+	}
+
+	webhook.Insecure = true
+	recorder = httptest.NewRecorder()
+	webhook.ServeHTTP(recorder, httptest.NewRequest("POST", "/notifications", bytes.NewReader(body)))
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 for an Insecure webhook, got %d", recorder.Code)
+	}
+}
+
+// TestWebhookDefaultMaxSkew verifies that the webhook rejects old notifications, e.g. replays,
+// when the Verifier doesn't set MaxSkew.
+func TestWebhookDefaultMaxSkew(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhook := &account.Webhook{Verifier: &account.Verifier{Keys: map[string]*rsa.PublicKey{TestKeyID: &key.PublicKey}}}
+	body := []byte(`{"id":"` + uuid.New().String() + `","record_type":"accounts","data":{}}`)
+	send := func(date time.Time) int {
+		req := httptest.NewRequest("POST", "/notifications", bytes.NewReader(body))
+		signAt(t, req, key, body, date)
+		recorder := httptest.NewRecorder()
+		webhook.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	if status := send(time.Now()); status != http.StatusNoContent {
+		t.Errorf("Expected 204 for a current notification, got %d", status)
+	}
+	if status := send(time.Now().Add(-account.DefaultWebhookMaxSkew - time.Minute)); status != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an old notification, got %d", status)
+	}
+	if webhook.Verifier.MaxSkew != 0 {
+		t.Errorf("Expected the Verifier to be unchanged, got MaxSkew %v", webhook.Verifier.MaxSkew)
+	}
+}
+
+// signAt signs req like Signer, but with the Date header set to date.
+func signAt(t *testing.T, req *http.Request, key *rsa.PrivateKey, body []byte, date time.Time) {
+	sum := sha256.Sum256(body)
+	req.Header.Set("Date", date.UTC().Format(http.TimeFormat))
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]))
+	signingString := fmt.Sprintf("(request-target): post %s\nhost: %s\ndate: %s\ndigest: %s",
+		req.URL.RequestURI(), req.Host, req.Header.Get("Date"), req.Header.Get("Digest"))
+	hashed := sha256.Sum256([]byte(signingString))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(`Signature keyId="%s",algorithm="%s",headers="(request-target) host date digest",signature="%s"`,
+		TestKeyID, account.SignatureAlgorithm, base64.StdEncoding.EncodeToString(signature)))
+}